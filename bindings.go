@@ -0,0 +1,610 @@
+package wlc
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ModeDefault is the binding mode active initially.
+const ModeDefault = "default"
+
+// modifierNames maps modifier names used in binding specs to modifier bits.
+var modifierNames = map[string]uint32{
+	"shift":   uint32(BitModShift),
+	"caps":    BitModCaps,
+	"lock":    BitModCaps,
+	"ctrl":    BitModCtrl,
+	"control": BitModCtrl,
+	"alt":     BitModAlt,
+	"mod1":    BitModAlt,
+	"mod2":    BitModMod2,
+	"mod3":    BitModMod3,
+	"logo":    BitModLogo,
+	"super":   BitModLogo,
+	"mod4":    BitModLogo,
+	"mod5":    BitModMod5,
+}
+
+// modifierOrder is the order modifiers are written in by KeyCombo.String.
+var modifierOrder = []struct {
+	name string
+	bit  uint32
+}{
+	{"Logo", BitModLogo},
+	{"Ctrl", BitModCtrl},
+	{"Alt", BitModAlt},
+	{"Shift", uint32(BitModShift)},
+	{"Caps", BitModCaps},
+	{"Mod2", BitModMod2},
+	{"Mod3", BitModMod3},
+	{"Mod5", BitModMod5},
+}
+
+// KeyCombo is a keysym pressed while holding exactly the modifiers Mods.
+type KeyCombo struct {
+	Mods   uint32
+	Keysym uint32
+}
+
+// ParseKeyCombo parses a key combination like "Logo+Shift+Return".
+// Modifiers are Shift, Caps (or Lock), Ctrl (or Control), Alt (or Mod1),
+// Mod2, Mod3, Logo (or Super, Mod4) and Mod5, matched case insensitively. The
+// key is a keysym name, see KeysymFromName. Letters match regardless of case,
+// since keys are matched by their keysym without modifiers applied. Keysyms
+// typed with Shift match with Shift held, e.g. "Ctrl+plus" matches
+// Ctrl+Shift+equal on a US layout.
+func ParseKeyCombo(spec string) (KeyCombo, error) {
+	var combo KeyCombo
+
+	key, mods := spec, spec
+	if i := strings.LastIndex(spec, "+"); i >= 0 && i == len(spec)-1 && (i == 0 || spec[i-1] == '+') {
+		// "Ctrl++" binds the plus key.
+		key = "plus"
+		mods = spec[:i]
+	}
+
+	parts := strings.Split(mods, "+")
+	if key != "plus" {
+		key = parts[len(parts)-1]
+		parts = parts[:len(parts)-1]
+	} else if len(parts) > 0 {
+		parts = parts[:len(parts)-1]
+	}
+
+	for _, part := range parts {
+		mod, ok := modifierNames[strings.ToLower(strings.TrimSpace(part))]
+		if !ok {
+			return KeyCombo{}, fmt.Errorf("unknown modifier '%s' in '%s'", part, spec)
+		}
+		combo.Mods |= mod
+	}
+
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return KeyCombo{}, fmt.Errorf("missing key in '%s'", spec)
+	}
+
+	sym := KeysymFromName(key)
+	if sym == 0 {
+		return KeyCombo{}, fmt.Errorf("unknown key '%s' in '%s'", key, spec)
+	}
+	combo.Keysym = keysymToLower(sym)
+
+	return combo, nil
+}
+
+// String gets the spec of the combination, e.g. "Logo+Shift+Return".
+func (k KeyCombo) String() string {
+	var b strings.Builder
+	for _, mod := range modifierOrder {
+		if k.Mods&mod.bit != 0 {
+			b.WriteString(mod.name)
+			b.WriteByte('+')
+		}
+	}
+	b.WriteString(KeysymName(k.Keysym))
+	return b.String()
+}
+
+// ParseKeySequence parses a space separated sequence of key combinations
+// like "Ctrl+x Ctrl+f", see ParseKeyCombo.
+func ParseKeySequence(spec string) ([]KeyCombo, error) {
+	fields := strings.Fields(spec)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty key sequence")
+	}
+
+	sequence := make([]KeyCombo, 0, len(fields))
+	for _, field := range fields {
+		combo, err := ParseKeyCombo(field)
+		if err != nil {
+			return nil, err
+		}
+		sequence = append(sequence, combo)
+	}
+
+	return sequence, nil
+}
+
+// keysymToLower gets the lower case keysym of Latin-1 letters.
+func keysymToLower(sym uint32) uint32 {
+	switch {
+	case sym >= 'A' && sym <= 'Z':
+		return sym + 0x20
+	case sym >= 0xc0 && sym <= 0xde && sym != 0xd7:
+		return sym + 0x20
+	}
+	return sym
+}
+
+// keysymIsModifier checks if sym is a modifier key like Shift_L or Super_R.
+func keysymIsModifier(sym uint32) bool {
+	return (sym >= 0xffe1 && sym <= 0xffee) || (sym >= 0xfe01 && sym <= 0xfe13)
+}
+
+// KeyBinding binds a sequence of key combinations to an action.
+type KeyBinding struct {
+	// Mode is the binding mode the binding is active in. Empty means
+	// ModeDefault.
+	Mode     string
+	Sequence []KeyCombo
+	// Release fires the action when the last key of the sequence is
+	// released instead of pressed.
+	Release bool
+	// Action is called with the focused view, or 0.
+	Action func(view View)
+	// Description describes the binding when listed.
+	Description string
+	// Repeat runs the action repeatedly while the last key of the sequence
+	// is held. Not used by release bindings.
+	Repeat bool
+	// RepeatDelay is the time before the action is repeated. 0 means the
+	// delay of wlc, see KeyRepeatInfo.
+	RepeatDelay time.Duration
+	// RepeatRate is the number of repeats per second. 0 means the rate of
+	// wlc, see KeyRepeatInfo.
+	RepeatRate int
+}
+
+// String gets the spec of the binding sequence.
+func (b *KeyBinding) String() string {
+	specs := make([]string, len(b.Sequence))
+	for i, combo := range b.Sequence {
+		specs[i] = combo.String()
+	}
+	return strings.Join(specs, " ")
+}
+
+func (b *KeyBinding) mode() string {
+	if b.Mode == "" {
+		return ModeDefault
+	}
+	return b.Mode
+}
+
+// conflicts checks if b and other can't be told apart, i.e. they are in the
+// same mode and one sequence is a prefix of the other. Press and release
+// bindings of the same sequence don't conflict.
+func (b *KeyBinding) conflicts(other *KeyBinding) bool {
+	if b.mode() != other.mode() {
+		return false
+	}
+
+	n := len(b.Sequence)
+	if len(other.Sequence) < n {
+		n = len(other.Sequence)
+	}
+
+	for i := 0; i < n; i++ {
+		if b.Sequence[i] != other.Sequence[i] {
+			return false
+		}
+	}
+
+	return len(b.Sequence) != len(other.Sequence) || b.Release == other.Release
+}
+
+// KeyRepeatInfo gets the key repeat delay and rate, in repeats per second,
+// used by wlc. They are read from the WLC_REPEAT_DELAY (in milliseconds) and
+// WLC_REPEAT_RATE environment variables when wlc is initialized, and default
+// to 400 ms and 25 repeats per second.
+func KeyRepeatInfo() (delay time.Duration, rate int) {
+	delay = 400 * time.Millisecond
+	rate = 25
+
+	if v, err := strconv.ParseUint(os.Getenv("WLC_REPEAT_DELAY"), 10, 32); err == nil {
+		delay = time.Duration(v) * time.Millisecond
+	}
+	if v, err := strconv.ParseUint(os.Getenv("WLC_REPEAT_RATE"), 10, 32); err == nil {
+		rate = int(v)
+	}
+
+	return delay, rate
+}
+
+// KeyBindings matches key events against key bindings. Modifiers are matched
+// exactly, ignoring IgnoreMods, so a binding for "Ctrl+q" doesn't fire for
+// "Ctrl+Shift+q". Keys are matched by their keysym without modifiers applied
+// or, if that doesn't match, by their keysym with Shift applied and Shift
+// removed from the modifiers, so bindings like "Logo+exclam" work.
+//
+// Bindings are grouped in modes, like i3, and only bindings of the current
+// mode are active. Sequences of more than one combination, like
+// "Ctrl+x Ctrl+f", are aborted if the next key doesn't continue the sequence
+// or isn't pressed within Timeout. Pressing modifier keys doesn't abort a
+// sequence.
+//
+// Bindings with Repeat are repeated while their key is held, driven by a
+// timer of the wlc event loop, since wlc only repeats keys for clients.
+// Repeating stops when the key or a modifier is released, another key is
+// pressed or focus changes.
+//
+// Call KeyboardKey from the keyboard key callback before any other handling
+// of the event, and ViewFocus from the view focus callback.
+type KeyBindings struct {
+	// Timeout is the time allowed between the keys of a sequence. 0 means
+	// no timeout.
+	Timeout time.Duration
+	// IgnoreMods are modifiers ignored when matching, by default Caps and
+	// Mod2 (Num Lock).
+	IgnoreMods uint32
+	// OnModeChange is called when the mode changes, if not nil.
+	OnModeChange func(mode string)
+
+	bindings []*KeyBinding
+	mode     string
+	pending  []KeyCombo
+	timer    EventSource
+	// keys whose press was consumed, so their release is consumed too.
+	consumed map[uint32]*KeyBinding
+	// binding repeating while key is held.
+	repeat      *KeyBinding
+	repeatKey   uint32
+	repeatView  View
+	repeatTimer EventSource
+}
+
+// NewKeyBindings initializes key bindings with a sequence timeout of one
+// second.
+func NewKeyBindings() *KeyBindings {
+	return &KeyBindings{
+		Timeout:    time.Second,
+		IgnoreMods: BitModCaps | BitModMod2,
+		mode:       ModeDefault,
+		consumed:   make(map[uint32]*KeyBinding),
+	}
+}
+
+// Add adds binding. Returns error if the sequence is empty or the binding
+// conflicts with an existing binding.
+func (k *KeyBindings) Add(binding KeyBinding) (*KeyBinding, error) {
+	if len(binding.Sequence) == 0 {
+		return nil, fmt.Errorf("empty key sequence")
+	}
+
+	b := &binding
+	for _, other := range k.bindings {
+		if b.conflicts(other) {
+			return nil, fmt.Errorf("'%s' conflicts with '%s' in mode '%s'", b, other, b.mode())
+		}
+	}
+
+	k.bindings = append(k.bindings, b)
+	return b, nil
+}
+
+// Bind binds spec, parsed by ParseKeySequence, to action in mode.
+func (k *KeyBindings) Bind(mode, spec string, action func(view View)) (*KeyBinding, error) {
+	sequence, err := ParseKeySequence(spec)
+	if err != nil {
+		return nil, err
+	}
+	return k.Add(KeyBinding{Mode: mode, Sequence: sequence, Action: action})
+}
+
+// BindRelease is like Bind but fires action when the key is released.
+func (k *KeyBindings) BindRelease(mode, spec string, action func(view View)) (*KeyBinding, error) {
+	sequence, err := ParseKeySequence(spec)
+	if err != nil {
+		return nil, err
+	}
+	return k.Add(KeyBinding{Mode: mode, Sequence: sequence, Release: true, Action: action})
+}
+
+// Remove removes binding.
+func (k *KeyBindings) Remove(binding *KeyBinding) {
+	for i, b := range k.bindings {
+		if b == binding {
+			k.bindings = append(k.bindings[:i], k.bindings[i+1:]...)
+			break
+		}
+	}
+
+	for key, b := range k.consumed {
+		if b == binding {
+			k.consumed[key] = nil
+		}
+	}
+
+	if binding == k.repeat {
+		k.stopRepeat()
+	}
+}
+
+// Bindings gets the bindings of mode in the order they were added. Empty mode
+// gets the bindings of all modes.
+func (k *KeyBindings) Bindings(mode string) []*KeyBinding {
+	var bindings []*KeyBinding
+	for _, b := range k.bindings {
+		if mode == "" || b.mode() == mode {
+			bindings = append(bindings, b)
+		}
+	}
+	return bindings
+}
+
+// Modes gets the modes having bindings, in the order they were first used.
+func (k *KeyBindings) Modes() []string {
+	var modes []string
+	seen := make(map[string]bool)
+	for _, b := range k.bindings {
+		if mode := b.mode(); !seen[mode] {
+			seen[mode] = true
+			modes = append(modes, mode)
+		}
+	}
+	return modes
+}
+
+// Mode gets the current mode.
+func (k *KeyBindings) Mode() string {
+	return k.mode
+}
+
+// SetMode switches to mode, aborting any pending sequence. Empty mode means
+// ModeDefault.
+func (k *KeyBindings) SetMode(mode string) {
+	if mode == "" {
+		mode = ModeDefault
+	}
+
+	k.reset()
+	k.stopRepeat()
+	if mode == k.mode {
+		return
+	}
+
+	k.mode = mode
+	if k.OnModeChange != nil {
+		k.OnModeChange(mode)
+	}
+}
+
+// Pending gets the combinations of the sequence typed so far.
+func (k *KeyBindings) Pending() []KeyCombo {
+	pending := make([]KeyCombo, len(k.pending))
+	copy(pending, k.pending)
+	return pending
+}
+
+// KeyboardKey runs the action of the binding matching the key event. Returns
+// true if the event is part of a binding, meaning it's not sent to clients.
+// The key aborting a sequence is consumed as well.
+func (k *KeyBindings) KeyboardKey(view View, time uint32, modifiers Modifiers, key uint32, state KeyState) bool {
+	sym := KeyboardGetKeysymForKey(key, nil)
+	shifted := sym
+	if state == KeyStatePressed && modifiers.Mods&uint32(BitModShift) != 0 {
+		shifted = KeyboardGetKeysymForKey(key, &modifiers)
+	}
+	return k.key(view, modifiers.Mods, key, sym, shifted, state)
+}
+
+// key handles a key event of key with keysym sym without modifiers applied,
+// and shifted with the modifiers applied.
+func (k *KeyBindings) key(view View, mods uint32, key, sym, shifted uint32, state KeyState) bool {
+	if k.repeat != nil && k.stopsRepeat(mods, key, sym, state) {
+		k.stopRepeat()
+	}
+
+	if state != KeyStatePressed {
+		b, ok := k.consumed[key]
+		if !ok {
+			return false
+		}
+
+		delete(k.consumed, key)
+		if b != nil && b.Action != nil {
+			b.Action(view)
+		}
+		return true
+	}
+
+	combo := KeyCombo{Mods: mods &^ k.IgnoreMods, Keysym: keysymToLower(sym)}
+	sequence := append(k.pending, combo)
+	press, release, prefix := k.match(sequence)
+
+	// keysyms typed with Shift, unless Shift only changes the case.
+	if press == nil && release == nil && !prefix && keysymToLower(shifted) != combo.Keysym {
+		combo = KeyCombo{Mods: combo.Mods &^ uint32(BitModShift), Keysym: shifted}
+		sequence[len(sequence)-1] = combo
+		press, release, prefix = k.match(sequence)
+	}
+
+	if press != nil || release != nil {
+		k.reset()
+		k.consumed[key] = release
+		if press != nil && press.Action != nil {
+			press.Action(view)
+			if press.Repeat {
+				k.startRepeat(press, key, view)
+			}
+		}
+		return true
+	}
+
+	if prefix {
+		k.pending = sequence
+		k.consumed[key] = nil
+		k.arm()
+		return true
+	}
+
+	if len(k.pending) == 0 || keysymIsModifier(sym) {
+		return false
+	}
+
+	k.reset()
+	k.consumed[key] = nil
+	return true
+}
+
+// stopsRepeat checks if the key event stops repeating: any key pressed, the
+// repeating key released, or a modifier of the binding released.
+func (k *KeyBindings) stopsRepeat(mods uint32, key, sym uint32, state KeyState) bool {
+	if state == KeyStatePressed || key == k.repeatKey {
+		return true
+	}
+
+	// wlc may report the modifiers from before the release, so releasing
+	// any modifier key stops repeating as well.
+	if keysymIsModifier(sym) {
+		return true
+	}
+
+	combo := k.repeat.Sequence[len(k.repeat.Sequence)-1]
+	return combo.Mods&^(mods&^k.IgnoreMods) != 0
+}
+
+// match gets the press and release bindings of the current mode matching
+// sequence, and if sequence is the prefix of a longer binding.
+func (k *KeyBindings) match(sequence []KeyCombo) (press, release *KeyBinding, prefix bool) {
+	for _, b := range k.bindings {
+		if b.mode() != k.mode || len(b.Sequence) < len(sequence) {
+			continue
+		}
+
+		if !sequenceHasPrefix(b.Sequence, sequence) {
+			continue
+		}
+
+		switch {
+		case len(b.Sequence) > len(sequence):
+			prefix = true
+		case b.Release:
+			release = b
+		default:
+			press = b
+		}
+	}
+	return press, release, prefix
+}
+
+// ViewFocus stops repeating when focus changes.
+func (k *KeyBindings) ViewFocus(view View, focus bool) {
+	k.stopRepeat()
+}
+
+// startRepeat starts repeating the action of b while key is held.
+func (k *KeyBindings) startRepeat(b *KeyBinding, key uint32, view View) {
+	delay, _ := KeyRepeatInfo()
+	if b.RepeatDelay > 0 {
+		delay = b.RepeatDelay
+	}
+
+	if k.repeatTimer == nil {
+		k.repeatTimer = EventLoopAddTimer(func(interface{}) {
+			k.repeatAction()
+		}, nil)
+		if k.repeatTimer == nil {
+			return
+		}
+	}
+
+	k.repeat = b
+	k.repeatKey = key
+	k.repeatView = view
+	EventSourceTimerUpdate(k.repeatTimer, durationMs(delay))
+}
+
+// repeatAction runs the repeating action and schedules the next repeat.
+func (k *KeyBindings) repeatAction() {
+	b := k.repeat
+	if b == nil {
+		return
+	}
+
+	_, rate := KeyRepeatInfo()
+	if b.RepeatRate > 0 {
+		rate = b.RepeatRate
+	}
+	if rate <= 0 {
+		k.stopRepeat()
+		return
+	}
+
+	// schedule first, the action may stop repeating.
+	EventSourceTimerUpdate(k.repeatTimer, durationMs(time.Second/time.Duration(rate)))
+	if b.Action != nil {
+		b.Action(k.repeatView)
+	}
+}
+
+// stopRepeat stops repeating.
+func (k *KeyBindings) stopRepeat() {
+	if k.repeat == nil {
+		return
+	}
+
+	k.repeat = nil
+	k.repeatView = 0
+	if k.repeatTimer != nil {
+		EventSourceTimerUpdate(k.repeatTimer, 0)
+	}
+}
+
+// durationMs converts d to milliseconds for EventSourceTimerUpdate, at least
+// 1 since 0 disarms the timer.
+func durationMs(d time.Duration) int32 {
+	if ms := int32(d / time.Millisecond); ms > 0 {
+		return ms
+	}
+	return 1
+}
+
+func sequenceHasPrefix(sequence, prefix []KeyCombo) bool {
+	for i, combo := range prefix {
+		if sequence[i] != combo {
+			return false
+		}
+	}
+	return true
+}
+
+// arm starts the sequence timeout.
+func (k *KeyBindings) arm() {
+	if k.Timeout <= 0 {
+		return
+	}
+
+	if k.timer == nil {
+		k.timer = EventLoopAddTimer(func(interface{}) {
+			k.pending = nil
+		}, nil)
+		if k.timer == nil {
+			return
+		}
+	}
+
+	EventSourceTimerUpdate(k.timer, int32(k.Timeout/time.Millisecond))
+}
+
+// reset aborts the pending sequence.
+func (k *KeyBindings) reset() {
+	k.pending = nil
+	if k.timer != nil {
+		EventSourceTimerUpdate(k.timer, 0)
+	}
+}
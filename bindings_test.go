@@ -0,0 +1,228 @@
+package wlc
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// keysyms used by the tests, normally found in xkbcommon-keysyms.h.
+const (
+	keysymShiftL = 0xffe1
+	keysymExclam = 0x21
+	keysymPlus   = 0x2b
+	keysymEqual  = 0x3d
+)
+
+func TestParseKeyCombo(t *testing.T) {
+	for _, tc := range []struct {
+		spec string
+		exp  string
+	}{
+		{"Logo+Shift+Return", "Logo+Shift+Return"},
+		{"super+Q", "Logo+q"},
+		{"Mod1+space", "Alt+space"},
+		{"Ctrl++", "Ctrl+plus"},
+		{"Ctrl+plus", "Ctrl+plus"},
+		{"Logo+exclam", "Logo+exclam"},
+		{"Control+Mod4+F1", "Logo+Ctrl+F1"},
+		{"Escape", "Escape"},
+	} {
+		combo, err := ParseKeyCombo(tc.spec)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.spec, err)
+			continue
+		}
+
+		if combo.String() != tc.exp {
+			t.Errorf("%s: expected '%s', got '%s'", tc.spec, tc.exp, combo)
+		}
+	}
+
+	for _, spec := range []string{
+		"",
+		"Ctrl+",
+		"Hyper+a",
+		"Ctrl+NoSuchKey",
+		"Ctrl+Shift",
+	} {
+		if _, err := ParseKeyCombo(spec); err == nil {
+			t.Errorf("%s: expected error", spec)
+		}
+	}
+}
+
+func TestParseKeySequence(t *testing.T) {
+	sequence, err := ParseKeySequence("Ctrl+x  Ctrl+f")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []KeyCombo{{Mods: BitModCtrl, Keysym: 'x'}, {Mods: BitModCtrl, Keysym: 'f'}}
+	if !reflect.DeepEqual(sequence, exp) {
+		t.Errorf("expected %v, got %v", exp, sequence)
+	}
+
+	for _, spec := range []string{"", "   ", "Ctrl+x Hyper+f"} {
+		if _, err := ParseKeySequence(spec); err == nil {
+			t.Errorf("'%s': expected error", spec)
+		}
+	}
+}
+
+func TestKeyBindings(t *testing.T) {
+	k := NewKeyBindings()
+	k.Timeout = 0
+
+	var fired []string
+	var modes []string
+	k.OnModeChange = func(mode string) {
+		modes = append(modes, mode)
+	}
+
+	for _, b := range []struct {
+		mode    string
+		spec    string
+		release bool
+	}{
+		{"", "Ctrl+q", false},
+		{"", "Ctrl+q", true},
+		{"", "Ctrl+x Ctrl+f", false},
+		{"", "Ctrl+plus", false},
+		{"", "Logo+Shift+q", false},
+		{"", "Logo+exclam", false},
+		{"resize", "Escape", false},
+	} {
+		bind := k.Bind
+		if b.release {
+			bind = k.BindRelease
+		}
+
+		spec := b.spec
+		if _, err := bind(b.mode, spec, func(View) { fired = append(fired, spec) }); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := k.Bind("", "Ctrl+x", nil); err == nil {
+		t.Error("expected prefix of a sequence to conflict")
+	}
+
+	if _, err := k.Bind("resize", "Ctrl+x", nil); err != nil {
+		t.Errorf("expected no conflict in another mode, got %v", err)
+	}
+
+	ctrl := uint32(BitModCtrl)
+	shift := uint32(BitModShift)
+	logo := uint32(BitModLogo)
+
+	for _, tc := range []struct {
+		name     string
+		mode     string
+		mods     uint32
+		key      uint32
+		sym      uint32
+		shifted  uint32
+		state    KeyState
+		consumed bool
+		fired    []string
+	}{
+		{name: "extra modifier", mods: ctrl | shift, key: 16, sym: 'q', shifted: 'Q', state: KeyStatePressed},
+		{name: "extra modifier release", mods: ctrl | shift, key: 16, sym: 'q', state: KeyStateReleased},
+		{name: "ignored modifier", mods: ctrl | BitModMod2, key: 16, sym: 'q', shifted: 'q', state: KeyStatePressed, consumed: true, fired: []string{"Ctrl+q"}},
+		{name: "release binding", mods: ctrl, key: 16, sym: 'q', state: KeyStateReleased, consumed: true, fired: []string{"Ctrl+q"}},
+		{name: "sequence start", mods: ctrl, key: 45, sym: 'x', shifted: 'x', state: KeyStatePressed, consumed: true},
+		{name: "sequence release", mods: ctrl, key: 45, sym: 'x', state: KeyStateReleased, consumed: true},
+		{name: "modifier in sequence", mods: ctrl, key: 42, sym: keysymShiftL, shifted: keysymShiftL, state: KeyStatePressed},
+		{name: "sequence end", mods: ctrl, key: 33, sym: 'f', shifted: 'f', state: KeyStatePressed, consumed: true, fired: []string{"Ctrl+x Ctrl+f"}},
+		{name: "sequence abort start", mods: ctrl, key: 45, sym: 'x', shifted: 'x', state: KeyStatePressed, consumed: true},
+		{name: "sequence abort", mods: ctrl, key: 34, sym: 'g', shifted: 'g', state: KeyStatePressed, consumed: true},
+		{name: "after abort", mods: ctrl, key: 34, sym: 'g', shifted: 'g', state: KeyStatePressed},
+		{name: "shifted keysym", mods: ctrl | shift, key: 13, sym: keysymEqual, shifted: keysymPlus, state: KeyStatePressed, consumed: true, fired: []string{"Ctrl+plus"}},
+		{name: "unshifted keysym", mods: ctrl, key: 13, sym: keysymEqual, shifted: keysymEqual, state: KeyStatePressed},
+		{name: "shift changing case", mods: logo | shift, key: 16, sym: 'q', shifted: 'Q', state: KeyStatePressed, consumed: true, fired: []string{"Logo+Shift+q"}},
+		{name: "shifted digit", mods: logo | shift, key: 2, sym: '1', shifted: keysymExclam, state: KeyStatePressed, consumed: true, fired: []string{"Logo+exclam"}},
+		{name: "shift changing case only", mods: ctrl | shift, key: 45, sym: 'x', shifted: 'X', state: KeyStatePressed},
+		{name: "other mode", mode: "resize", mods: ctrl, key: 16, sym: 'q', shifted: 'q', state: KeyStatePressed},
+		{name: "mode binding", mode: "resize", key: 1, sym: keysymEscape, shifted: keysymEscape, state: KeyStatePressed, consumed: true, fired: []string{"Escape"}},
+		{name: "default mode", mode: ModeDefault, key: 1, sym: keysymEscape, shifted: keysymEscape, state: KeyStatePressed},
+	} {
+		if tc.mode != "" {
+			k.SetMode(tc.mode)
+		}
+
+		fired = nil
+		if consumed := k.key(0, tc.mods, tc.key, tc.sym, tc.shifted, tc.state); consumed != tc.consumed {
+			t.Errorf("%s: expected consumed %t, got %t", tc.name, tc.consumed, consumed)
+		}
+
+		if !reflect.DeepEqual(fired, tc.fired) {
+			t.Errorf("%s: expected %v to fire, got %v", tc.name, tc.fired, fired)
+		}
+	}
+
+	if exp := []string{"resize", ModeDefault}; !reflect.DeepEqual(modes, exp) {
+		t.Errorf("expected mode changes %v, got %v", exp, modes)
+	}
+
+	if n := len(k.Bindings(ModeDefault)); n != 6 {
+		t.Errorf("expected 6 bindings in mode default, got %d", n)
+	}
+
+	if exp := []string{ModeDefault, "resize"}; !reflect.DeepEqual(k.Modes(), exp) {
+		t.Errorf("expected modes %v, got %v", exp, k.Modes())
+	}
+}
+
+func TestKeyBindingsStopsRepeat(t *testing.T) {
+	k := NewKeyBindings()
+	b, err := k.Bind("", "Logo+Up", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// repeating Logo+Up, Up is key 103.
+	k.repeat = b
+	k.repeatKey = 103
+
+	logo := uint32(BitModLogo)
+	for _, tc := range []struct {
+		name  string
+		mods  uint32
+		key   uint32
+		sym   uint32
+		state KeyState
+		exp   bool
+	}{
+		{"other key pressed", logo, 30, 'a', KeyStatePressed, true},
+		{"key released", logo, 103, 0xff52, KeyStateReleased, true},
+		{"other key released", logo, 30, 'a', KeyStateReleased, false},
+		{"ignored modifier released", logo | BitModMod2, 30, 'a', KeyStateReleased, false},
+		{"modifier key released", logo, 125, 0xffeb, KeyStateReleased, true},
+		{"modifier no longer held", 0, 30, 'a', KeyStateReleased, true},
+	} {
+		if stops := k.stopsRepeat(tc.mods, tc.key, tc.sym, tc.state); stops != tc.exp {
+			t.Errorf("%s: expected stop %t, got %t", tc.name, tc.exp, stops)
+		}
+	}
+}
+
+func TestKeyRepeatInfo(t *testing.T) {
+	for _, tc := range []struct {
+		delay string
+		rate  string
+		exp   time.Duration
+		rexp  int
+	}{
+		{"", "", 400 * time.Millisecond, 25},
+		{"200", "50", 200 * time.Millisecond, 50},
+		{"x", "-1", 400 * time.Millisecond, 25},
+	} {
+		t.Setenv("WLC_REPEAT_DELAY", tc.delay)
+		t.Setenv("WLC_REPEAT_RATE", tc.rate)
+
+		if delay, rate := KeyRepeatInfo(); delay != tc.exp || rate != tc.rexp {
+			t.Errorf("'%s', '%s': expected %v and %d, got %v and %d", tc.delay, tc.rate, tc.exp, tc.rexp, delay, rate)
+		}
+	}
+}
@@ -15,16 +15,19 @@ void wrap_wlc_log_set_handler() {
 
 int event_loop_fd_cb(int fd, uint32_t mask, void *arg) {
 	_goEventLoopFdCb(fd, mask);
+	return 0;
 }
 
 struct wlc_event_source *wrap_wlc_event_loop_add_fd(int fd, uint32_t mask) {
-	wlc_event_loop_add_fd(fd, mask, event_loop_fd_cb, NULL);
+	return wlc_event_loop_add_fd(fd, mask, event_loop_fd_cb, NULL);
 }
 
 int event_loop_timer_cb(void *arg) {
-	_goEventLoopTimerCb(*((uint32_t*)arg));
+	// the timer id is stored in the pointer itself.
+	_goEventLoopTimerCb((uint32_t)(uintptr_t)arg);
+	return 0;
 }
 
 struct wlc_event_source *wrap_wlc_event_loop_add_timer(uint32_t id) {
-	wlc_event_loop_add_timer(event_loop_timer_cb, &id);
+	return wlc_event_loop_add_timer(event_loop_timer_cb, (void*)(uintptr_t)id);
 }
@@ -148,7 +148,7 @@ func timerEventID() uint32 {
 }
 
 //export _goEventLoopTimerCb
-func _goEventLoopTimerCb(id C.int32_t) {
+func _goEventLoopTimerCb(id C.uint32_t) {
 	if event, ok := eventLoopTimer[uint32(id)]; ok {
 		event.cb(event.arg)
 	}
@@ -193,9 +193,10 @@ func EventSourceRemove(source EventSource) {
 	}
 
 	if !found {
-		for id, event := range eventLoopFd {
+		for id, event := range eventLoopTimer {
 			if source == event.source {
-				delete(eventLoopFd, id)
+				delete(eventLoopTimer, id)
+				break
 			}
 		}
 	}
@@ -6,86 +6,17 @@ import (
 	"os"
 
 	"github.com/mikkeloscar/go-wlc"
-	"github.com/mikkeloscar/go-xkbcommon"
-)
-
-// constants you would normally get from linux/input.h
-const (
-	btnLeft  = 0x110
-	btnRight = 0x111
 )
 
 // Compositor is a wayland compositor.
 type Compositor struct {
-	action *action
-}
-
-type action struct {
-	view  wlc.View
-	grab  wlc.Point
-	edges uint32
-}
-
-func (c *Compositor) startInteractiveAction(view wlc.View, origin wlc.Point) bool {
-	if c.action != nil {
-		return false
-	}
-
-	c.action = &action{
-		view: view,
-		grab: origin,
-	}
-	view.BringToFront()
-	return true
-}
-
-func (c *Compositor) startInteractiveMove(view wlc.View, origin wlc.Point) {
-	c.startInteractiveAction(view, origin)
-}
-
-func (c *Compositor) startInteractiveResize(view wlc.View, edges uint32, origin wlc.Point) {
-	g := view.GetGeometry()
-	if g == nil {
-		return
-	}
-
-	if !c.startInteractiveAction(view, origin) {
-		return
-	}
-
-	halfw := g.Origin.X + int32(g.Size.W)/2
-	halfh := g.Origin.Y + int32(g.Size.H)/2
-
-	if edges == 0 {
-		if origin.X < halfw {
-			edges = wlc.ResizeEdgeLeft
-		} else {
-			if origin.X > halfw {
-				edges = wlc.ResizeEdgeRight
-			}
-		}
-
-		if origin.Y < halfh {
-			edges |= wlc.ResizeEdgeTop
-		} else {
-			if origin.Y > halfh {
-				edges |= wlc.ResizeEdgeBottom
-			}
-		}
-	}
-
-	c.action.edges = edges
-
-	view.SetState(wlc.BitResizing, true)
-}
-
-func (c *Compositor) stopInteractiveAction() {
-	if c.action == nil {
-		return
-	}
-
-	c.action.view.SetState(wlc.BitResizing, false)
-	c.action = nil
+	interactive *wlc.Interactive
+	focus       *wlc.FocusManager
+	policies    *wlc.ViewPolicies
+	keys        *wlc.KeyBindings
+	buttons     *wlc.PointerBindings
+	pointer     *wlc.PointerController
+	layers      *wlc.Layers
 }
 
 func getTopmost(output wlc.Output, offset int) wlc.View {
@@ -103,7 +34,7 @@ func (c *Compositor) relayout(output wlc.Output) {
 		return
 	}
 
-	views := output.GetViews()
+	views := c.policies.FilterTiled(output.GetViews())
 
 	toggle := false
 	y := 0
@@ -147,33 +78,32 @@ func (c *Compositor) OutputResolution(output wlc.Output, from *wlc.Size, to *wlc
 // ViewCreated is the callback triggered when a view is created.
 func (c *Compositor) ViewCreated(view wlc.View) bool {
 	view.SetMask(view.GetOutput().GetMask())
-	view.BringToFront()
-	view.Focus()
+	c.layers.Raise(view)
+	c.policies.ViewCreated(view)
+	c.focus.ViewCreated(view)
+	if c.policies.Cycle(view) {
+		c.focus.Focus(view)
+	}
 	c.relayout(view.GetOutput())
 	return true
 }
 
 // ViewDestroyed is the callback triggered when a view is destroyed.
 func (c *Compositor) ViewDestroyed(view wlc.View) {
-	getTopmost(view.GetOutput(), 0).Focus()
+	c.interactive.ViewDestroyed(view)
+	c.pointer.ViewDestroyed(view)
+	c.focus.ViewDestroyed(view)
+	c.layers.ViewDestroyed(view)
 	c.relayout(view.GetOutput())
 }
 
 // ViewFocus is the callback triggered when a view is focused.
 func (c *Compositor) ViewFocus(view wlc.View, focus bool) {
+	c.focus.ViewFocus(view, focus)
+	c.keys.ViewFocus(view, focus)
 	view.SetState(wlc.BitActivated, focus)
 }
 
-// ViewRequestMove is the callback triggered when a view is moved.
-func (c *Compositor) ViewRequestMove(view wlc.View, origin *wlc.Point) {
-	c.startInteractiveMove(view, *origin)
-}
-
-// ViewRequestResize is the callback triggered when a view is resized.
-func (c *Compositor) ViewRequestResize(view wlc.View, edges uint32, origin *wlc.Point) {
-	c.startInteractiveResize(view, edges, *origin)
-}
-
 // ViewRequestGeometry is the callback triggered when a new view geometry is
 // requested.
 // Note this callback must allways be be set (even if it's stubbed) otherwise
@@ -184,114 +114,74 @@ func (c *Compositor) ViewRequestGeometry(view wlc.View, geometry *wlc.Geometry)
 
 // KeyboardKey is the callback triggered on keyboard presses.
 func (c *Compositor) KeyboardKey(view wlc.View, time uint32, modifiers wlc.Modifiers, key uint32, state wlc.KeyState) bool {
-	sym := wlc.KeyboardGetKeysymForKey(key, nil)
+	return c.keys.KeyboardKey(view, time, modifiers, key, state)
+}
 
-	if state == wlc.KeyStatePressed {
-		if view != 0 {
-			if (modifiers.Mods&wlc.BitModCtrl != 0) && sym == xkb.Keyq {
+func (c *Compositor) bindKeys() error {
+	bindings := []struct {
+		spec   string
+		action func(wlc.View)
+	}{
+		{"Ctrl+q", func(view wlc.View) {
+			if view != 0 {
 				view.Close()
-				return true
 			}
-
-			if (modifiers.Mods&wlc.BitModCtrl != 0) && sym == xkb.KeyDown {
-				view.SendToBack()
+		}},
+		{"Ctrl+Down", func(view wlc.View) {
+			if view != 0 {
+				c.layers.Lower(view)
 				getTopmost(view.GetOutput(), 0).Focus()
-				return true
 			}
-		}
-
-		if (modifiers.Mods&wlc.BitModCtrl != 0) && sym == xkb.KeyEscape {
+		}},
+		{"Ctrl+Escape", func(wlc.View) {
 			wlc.Terminate()
-			return true
-		}
-
-		if (modifiers.Mods&wlc.BitModCtrl != 0) && sym == xkb.KeyReturn {
+		}},
+		{"Ctrl+Return", func(wlc.View) {
 			term := os.Getenv("TERMINAL")
 			if len(term) == 0 {
 				term = "weston-terminal"
 			}
 			wlc.Exec(term)
-			return true
+		}},
+	}
+
+	for _, b := range bindings {
+		if _, err := c.keys.Bind(wlc.ModeDefault, b.spec, b.action); err != nil {
+			return err
 		}
 	}
 
-	return false
+	return nil
 }
 
 // PointerButton is the callback triggered on pointer button presses.
 func (c *Compositor) PointerButton(view wlc.View, time uint32, modifiers wlc.Modifiers, button uint32, state wlc.ButtonState, pos *wlc.Point) bool {
-	if state == wlc.ButtonStatePressed {
-		view.Focus()
-		if view != 0 {
-			if (modifiers.Mods&wlc.BitModCtrl != 0) && button == btnLeft {
-				c.startInteractiveMove(view, *pos)
-			}
+	c.focus.PointerButton(view, time, modifiers, button, state, pos)
+	handled := c.buttons.PointerButton(view, time, modifiers, button, state, pos)
+	return c.interactive.PointerButton(view, time, modifiers, button, state, pos) || handled
+}
 
-			if (modifiers.Mods&wlc.BitModCtrl != 0) && button == btnRight {
-				c.startInteractiveResize(view, 0, *pos)
-			}
+func (c *Compositor) bindButtons() error {
+	if _, err := c.buttons.Bind("Ctrl+Left", func(view wlc.View, pos wlc.Point) {
+		if view != 0 {
+			c.interactive.StartMove(view, pos)
 		}
-	} else {
-		c.stopInteractiveAction()
-	}
-
-	if c.action != nil {
-		return true
+	}); err != nil {
+		return err
 	}
 
-	return false
+	_, err := c.buttons.Bind("Ctrl+Right", func(view wlc.View, pos wlc.Point) {
+		if view != 0 {
+			c.interactive.StartResize(view, 0, pos)
+		}
+	})
+	return err
 }
 
 // PointerMotion is the callback triggered on pointer motions.
 func (c *Compositor) PointerMotion(view wlc.View, time uint32, pos *wlc.Point) bool {
-	if c.action != nil {
-		dx := pos.X - c.action.grab.X
-		dy := pos.Y - c.action.grab.Y
-		g := c.action.view.GetGeometry()
-
-		if c.action.edges != 0 {
-			min := wlc.Size{W: 80, H: 40}
-			n := *g
-			if c.action.edges&wlc.ResizeEdgeLeft != 0 {
-				n.Size.W -= uint32(dx)
-				n.Origin.X += dx
-			} else if c.action.edges&wlc.ResizeEdgeRight != 0 {
-				n.Size.W += uint32(dx)
-			}
-
-			if c.action.edges&wlc.ResizeEdgeTop != 0 {
-				n.Size.H -= uint32(dy)
-				n.Origin.Y += dy
-			} else if c.action.edges&wlc.ResizeEdgeBottom != 0 {
-				n.Size.H += uint32(dy)
-			}
-
-			if n.Size.W >= min.W {
-				g.Origin.X = n.Origin.X
-				g.Size.W = n.Size.W
-			}
-
-			if n.Size.H >= min.H {
-				g.Origin.Y = n.Origin.Y
-				g.Size.H = n.Size.H
-			}
-
-			c.action.view.SetGeometry(c.action.edges, *g)
-		} else {
-			g.Origin.X += dx
-			g.Origin.Y += dy
-			c.action.view.SetGeometry(0, *g)
-		}
-
-		c.action.grab = *pos
-	}
-
-	wlc.PointerSetPosition(*pos)
-	if c.action != nil {
-		return true
-	}
-
-	return false
+	c.pointer.PointerMotion(view, time, pos)
+	return c.interactive.PointerMotion(view, time, pos)
 }
 
 func cbLog(typ wlc.LogType, str string) {
@@ -301,14 +191,37 @@ func cbLog(typ wlc.LogType, str string) {
 func main() {
 	wlc.LogSetHandler(cbLog)
 
-	compositor := Compositor{}
+	compositor := Compositor{
+		interactive: wlc.NewInteractive(),
+		focus:       wlc.NewFocusManager(wlc.FocusClick),
+		policies:    wlc.NewViewPolicies(),
+		keys:        wlc.NewKeyBindings(),
+		buttons:     wlc.NewPointerBindings(),
+		pointer:     wlc.NewPointerController(nil),
+		layers:      wlc.NewLayers(),
+	}
+	// raise through layers, so views are restacked by layer afterwards.
+	compositor.interactive.RaiseView = compositor.layers.Raise
+	compositor.focus.Skip = func(view wlc.View) bool {
+		return !compositor.policies.Cycle(view)
+	}
+	compositor.interactive.MinSize = wlc.Size{W: 80, H: 40}
+	if err := compositor.bindKeys(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := compositor.bindButtons(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
 	wlc.SetOutputResolutionCb(compositor.OutputResolution)
 	wlc.SetViewCreatedCb(compositor.ViewCreated)
 	wlc.SetViewDestroyedCb(compositor.ViewDestroyed)
 	wlc.SetViewFocusCb(compositor.ViewFocus)
-	wlc.SetViewRequestMoveCb(compositor.ViewRequestMove)
-	wlc.SetViewRequestResizeCb(compositor.ViewRequestResize)
+	wlc.SetViewMoveToOutputCb(compositor.layers.ViewMoveToOutput)
+	wlc.SetViewRequestMoveCb(compositor.interactive.ViewRequestMove)
+	wlc.SetViewRequestResizeCb(compositor.interactive.ViewRequestResize)
 	wlc.SetViewRequestGeometryCb(compositor.ViewRequestGeometry)
 	wlc.SetKeyboardKeyCb(compositor.KeyboardKey)
 	wlc.SetPointerButtonCb(compositor.PointerButton)
@@ -0,0 +1,251 @@
+package wlc
+
+// FocusPolicy defines how pointer events move focus between views.
+type FocusPolicy int
+
+const (
+	// FocusClick focuses a view when it's clicked.
+	FocusClick FocusPolicy = iota
+	// FocusFollowsMouse focuses the view under the pointer and unfocuses
+	// all views when the pointer is not above any view.
+	FocusFollowsMouse
+	// FocusSloppy focuses the view under the pointer, but keeps focus when
+	// the pointer is not above any view.
+	FocusSloppy
+)
+
+// FocusManager keeps a most recently used history of focused views and moves
+// focus according to a focus policy. The per output history is the global
+// history filtered by the current output of each view.
+//
+// Call ViewCreated, ViewDestroyed and ViewFocus from the view callbacks and
+// PointerButton and PointerMotion from the pointer callbacks.
+type FocusManager struct {
+	Policy FocusPolicy
+	// Skip excludes views from focus cycling and refocusing, if not nil.
+	Skip func(View) bool
+
+	// history of views, most recently focused first.
+	history []View
+	focused View
+	// cycling is set while focus is moved by FocusNext/FocusPrev, the
+	// history is not reordered until the cycle ends.
+	cycling bool
+}
+
+// NewFocusManager initializes a focus manager with policy.
+func NewFocusManager(policy FocusPolicy) *FocusManager {
+	return &FocusManager{Policy: policy}
+}
+
+// Focused gets the focused view. Returns 0 if no view is focused.
+func (f *FocusManager) Focused() View {
+	return f.focused
+}
+
+// History gets all known views, most recently focused first.
+func (f *FocusManager) History() []View {
+	history := make([]View, len(f.history))
+	copy(history, f.history)
+	return history
+}
+
+// OutputHistory gets the views on output, most recently focused first.
+func (f *FocusManager) OutputHistory(output Output) []View {
+	var history []View
+	for _, view := range f.history {
+		if view.GetOutput() == output {
+			history = append(history, view)
+		}
+	}
+	return history
+}
+
+// Focus focuses view and makes it the most recently used. Focusing 0
+// unfocuses all views.
+func (f *FocusManager) Focus(view View) {
+	f.endCycle()
+	if view == 0 {
+		f.focused = 0
+		ViewUnfocus()
+		return
+	}
+
+	f.promote(view)
+	f.focused = view
+	view.Focus()
+}
+
+// ViewCreated adds view to the history as the least recently used view.
+// Always returns true so it can be used directly as view created callback.
+func (f *FocusManager) ViewCreated(view View) bool {
+	if f.index(view) == -1 {
+		f.history = append(f.history, view)
+	}
+	return true
+}
+
+// ViewDestroyed removes view from the history. If view was focused the most
+// recently used visible view on the same output is focused.
+func (f *FocusManager) ViewDestroyed(view View) {
+	f.remove(view)
+	if f.focused == view {
+		f.focused = 0
+		f.cycling = false
+		f.Focus(f.mostRecentVisible(view.GetOutput()))
+	}
+}
+
+// ViewFocus keeps the history in sync when focus is changed outside of the
+// manager.
+func (f *FocusManager) ViewFocus(view View, focus bool) {
+	if !focus {
+		if f.focused == view {
+			f.focused = 0
+		}
+		return
+	}
+
+	if !f.cycling {
+		f.promote(view)
+	}
+	f.focused = view
+}
+
+// PointerButton focuses the clicked view. Always returns false so the event
+// is passed on to clients.
+func (f *FocusManager) PointerButton(view View, time uint32, modifiers Modifiers, button uint32, state ButtonState, pos *Point) bool {
+	if state == ButtonStatePressed && view != 0 && view != f.focused {
+		f.Focus(view)
+	}
+	return false
+}
+
+// PointerMotion focuses the view under the pointer for the
+// FocusFollowsMouse and FocusSloppy policies. Always returns false so the
+// event is passed on to clients.
+func (f *FocusManager) PointerMotion(view View, time uint32, pos *Point) bool {
+	switch f.Policy {
+	case FocusFollowsMouse:
+		if view != f.focused {
+			f.Focus(view)
+		}
+	case FocusSloppy:
+		if view != 0 && view != f.focused {
+			f.Focus(view)
+		}
+	}
+	return false
+}
+
+// Refocus makes sure the focused view is visible. If it is not, the most
+// recently used visible view on the focused output is focused. Call this
+// after changing the visibility mask of views or outputs.
+func (f *FocusManager) Refocus() {
+	if f.focused != 0 && f.focused.Visible() {
+		return
+	}
+
+	f.Focus(f.mostRecentVisible(GetFocusedOutput()))
+}
+
+// SetViewMask sets the visibility bitmask of view and refocuses if needed.
+func (f *FocusManager) SetViewMask(view View, mask uint32) {
+	setViewMask(view, mask)
+	f.Refocus()
+}
+
+// SetOutputMask sets the visibility bitmask of output and refocuses if
+// needed.
+func (f *FocusManager) SetOutputMask(output Output, mask uint32) {
+	output.SetMask(mask)
+	f.Refocus()
+}
+
+// FocusNext focuses the next less recently used visible view on output,
+// wrapping around. The history is not reordered until the cycle is ended with
+// EndCycle or focus is changed by other means, so repeated calls visit all
+// views.
+func (f *FocusManager) FocusNext(output Output) {
+	f.cycle(output, 1)
+}
+
+// FocusPrev focuses the previous more recently used visible view on output,
+// wrapping around. See FocusNext.
+func (f *FocusManager) FocusPrev(output Output) {
+	f.cycle(output, -1)
+}
+
+// EndCycle ends focus cycling started by FocusNext or FocusPrev, making the
+// focused view the most recently used.
+func (f *FocusManager) EndCycle() {
+	f.endCycle()
+}
+
+func (f *FocusManager) cycle(output Output, step int) {
+	var views []View
+	for _, view := range f.OutputHistory(output) {
+		if f.candidate(view) {
+			views = append(views, view)
+		}
+	}
+
+	if len(views) == 0 {
+		return
+	}
+
+	next := 0
+	for i, view := range views {
+		if view == f.focused {
+			next = (i + step + len(views)) % len(views)
+			break
+		}
+	}
+
+	f.cycling = true
+	f.focused = views[next]
+	views[next].Focus()
+}
+
+func (f *FocusManager) endCycle() {
+	if f.cycling {
+		f.cycling = false
+		if f.focused != 0 {
+			f.promote(f.focused)
+		}
+	}
+}
+
+func (f *FocusManager) mostRecentVisible(output Output) View {
+	for _, view := range f.history {
+		if view.GetOutput() == output && f.candidate(view) {
+			return view
+		}
+	}
+	return 0
+}
+
+// candidate checks if view can be focused by cycling or refocusing.
+func (f *FocusManager) candidate(view View) bool {
+	return view.Visible() && (f.Skip == nil || !f.Skip(view))
+}
+
+func (f *FocusManager) promote(view View) {
+	f.remove(view)
+	f.history = append([]View{view}, f.history...)
+}
+
+func (f *FocusManager) remove(view View) {
+	if i := f.index(view); i != -1 {
+		f.history = append(f.history[:i], f.history[i+1:]...)
+	}
+}
+
+func (f *FocusManager) index(view View) int {
+	for i, v := range f.history {
+		if v == view {
+			return i
+		}
+	}
+	return -1
+}
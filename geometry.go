@@ -3,29 +3,6 @@ package wlc
 /*
 #cgo LDFLAGS: -lwlc
 #include <wlc/wlc.h>
-
-struct wlc_point *init_point(int32_t x, int32_t y) {
-	struct wlc_point *point = malloc(sizeof(struct wlc_point));
-	point->x = x;
-	point->y = y;
-	return point;
-}
-
-struct wlc_size *init_size(uint32_t w, uint32_t h) {
-	struct wlc_size *size = malloc(sizeof(struct wlc_size));
-	size->w = w;
-	size->h = h;
-	return size;
-}
-
-struct wlc_geometry *init_geometry(int32_t x, int32_t y, uint32_t w, uint32_t h) {
-	struct wlc_geometry *geometry = malloc(sizeof(struct wlc_geometry));
-	geometry->origin.x = x;
-	geometry->origin.y = y;
-	geometry->size.w = w;
-	geometry->size.h = h;
-	return geometry;
-}
 */
 import "C"
 import "math"
@@ -35,8 +12,13 @@ type Point struct {
 	X, Y int32
 }
 
-func (p *Point) c() *C.struct_wlc_point {
-	return C.init_point(C.int32_t(p.X), C.int32_t(p.Y))
+// c converts the point to a C struct by value. Passing it by value to a C
+// wrapper keeps it off the heap.
+func (p *Point) c() C.struct_wlc_point {
+	return C.struct_wlc_point{
+		x: C.int32_t(p.X),
+		y: C.int32_t(p.Y),
+	}
 }
 
 func pointCtoGo(c *C.struct_wlc_point) *Point {
@@ -55,8 +37,11 @@ type Size struct {
 	W, H uint32
 }
 
-func (s *Size) c() *C.struct_wlc_size {
-	return C.init_size(C.uint32_t(s.W), C.uint32_t(s.H))
+func (s *Size) c() C.struct_wlc_size {
+	return C.struct_wlc_size{
+		w: C.uint32_t(s.W),
+		h: C.uint32_t(s.H),
+	}
 }
 
 func sizeCtoGo(c *C.struct_wlc_size) *Size {
@@ -76,13 +61,11 @@ type Geometry struct {
 	Size   Size
 }
 
-func (g *Geometry) c() *C.struct_wlc_geometry {
-	return C.init_geometry(
-		C.int32_t(g.Origin.X),
-		C.int32_t(g.Origin.Y),
-		C.uint32_t(g.Size.W),
-		C.uint32_t(g.Size.H),
-	)
+func (g *Geometry) c() C.struct_wlc_geometry {
+	return C.struct_wlc_geometry{
+		origin: g.Origin.c(),
+		size:   g.Size.c(),
+	}
 }
 
 func geometryCtoGo(g *Geometry, c *C.struct_wlc_geometry) *Geometry {
@@ -0,0 +1,448 @@
+package wlc
+
+import (
+	"math"
+	"time"
+)
+
+// GestureType is the type of a touch gesture.
+type GestureType int
+
+const (
+	// GestureTap is one or more fingers touching briefly without moving.
+	GestureTap GestureType = iota
+	// GestureLongPress is one or more fingers touching without moving for
+	// LongPressTime.
+	GestureLongPress
+	// GestureSwipe is one or more fingers moving in the same direction.
+	GestureSwipe
+	// GesturePinch is two or more fingers moving towards or away from each
+	// other, or rotating.
+	GesturePinch
+)
+
+// GesturePhase is the phase of a gesture. Taps and long-presses only have
+// GestureEnd.
+type GesturePhase int
+
+const (
+	GestureBegin GesturePhase = iota
+	GestureUpdate
+	GestureEnd
+	GestureCancel
+)
+
+// SwipeDirection is the direction of a swipe.
+type SwipeDirection int
+
+const (
+	SwipeUp SwipeDirection = iota
+	SwipeDown
+	SwipeLeft
+	SwipeRight
+)
+
+// Gesture describes a recognized touch gesture.
+type Gesture struct {
+	Type    GestureType
+	Phase   GesturePhase
+	Fingers int
+	// View is the view touched by the first finger, or 0.
+	View View
+	// Center is the center of the fingers when the gesture started.
+	Center Point
+	// Delta is the movement of the center of the fingers.
+	Delta Point
+	// Direction is the direction of a swipe.
+	Direction SwipeDirection
+	// Scale is the distance between the fingers of a pinch relative to when
+	// it started.
+	Scale float64
+	// Rotation is the rotation of the fingers of a pinch in degrees,
+	// clockwise.
+	Rotation float64
+}
+
+type touchSlot struct {
+	start Point
+	pos   Point
+	// sent is set if the down was sent to clients.
+	sent bool
+}
+
+// GestureRecognizer recognizes taps, long-presses, swipes and pinches from
+// multi-touch events. Swipes and pinches are reported as they happen, with a
+// GestureBegin, any number of GestureUpdate and a GestureEnd, or GestureCancel
+// if the touch sequence is cancelled.
+//
+// If Handler returns true for a gesture, the rest of the touch sequence is
+// consumed and not sent to clients. Touch sequences reaching ConsumeFingers
+// fingers are consumed from that point on as well, so the handled gestures
+// aren't seen by clients at all. Fingers whose down was sent to clients
+// always have their up and cancel sent as well, so clients don't keep
+// touch points that are gone.
+//
+// Fingers are measured on TouchFrame, so movement of several fingers
+// reported one by one isn't mistaken for a pinch. Call Touch from the touch
+// callback before any other handling of the event.
+// Long-presses use a timer of the wlc event loop, so they are only recognized
+// after Init.
+type GestureRecognizer struct {
+	// Handler is called for recognized gestures.
+	Handler func(gesture Gesture) bool
+	// TapTime is the time within a tap must end.
+	TapTime time.Duration
+	// TapDistance is the distance a finger can move during a tap or
+	// long-press.
+	TapDistance int32
+	// LongPressTime is the time fingers must touch for a long-press.
+	LongPressTime time.Duration
+	// SwipeDistance is the distance fingers must move to start a swipe.
+	SwipeDistance int32
+	// PinchScale is the relative change of the distance between fingers
+	// starting a pinch.
+	PinchScale float64
+	// PinchRotation is the rotation in degrees starting a pinch.
+	PinchRotation float64
+	// ConsumeFingers is the number of fingers from which on touch sequences
+	// are consumed. 0 means only gestures accepted by Handler are
+	// consumed.
+	ConsumeFingers int
+
+	slots   map[int32]*touchSlot
+	view    View
+	start   uint32
+	fingers int
+	// moved is set if any finger of the touch sequence moved beyond
+	// TapDistance, including fingers already lifted.
+	moved bool
+	// sum and number of the down positions of the touch sequence, the
+	// center of a tap.
+	downs    [2]int64
+	ndowns   int64
+	gesture  *Gesture
+	consumed bool
+	// passed is set if an event of the current frame was sent to clients.
+	passed bool
+	timer  EventSource
+}
+
+// NewGestureRecognizer initializes a gesture recognizer calling handler.
+func NewGestureRecognizer(handler func(gesture Gesture) bool) *GestureRecognizer {
+	return &GestureRecognizer{
+		Handler:       handler,
+		TapTime:       250 * time.Millisecond,
+		TapDistance:   10,
+		LongPressTime: 500 * time.Millisecond,
+		SwipeDistance: 100,
+		PinchScale:    0.2,
+		PinchRotation: 15,
+		slots:         make(map[int32]*touchSlot),
+	}
+}
+
+// Touch tracks the touch event and reports recognized gestures. Returns true
+// if the touch sequence is consumed, meaning the event is not sent to
+// clients.
+func (g *GestureRecognizer) Touch(view View, time uint32, modifiers Modifiers, touch TouchType, slot int32, pos *Point) bool {
+	consumed := g.consumed
+
+	switch touch {
+	case TouchDown:
+		if len(g.slots) == 0 {
+			g.view = view
+			g.start = time
+			g.fingers = 0
+		}
+
+		s := &touchSlot{start: *pos, pos: *pos}
+		g.slots[slot] = s
+		g.downs[0] += int64(pos.X)
+		g.downs[1] += int64(pos.Y)
+		g.ndowns++
+		if len(g.slots) > g.fingers {
+			g.fingers = len(g.slots)
+		}
+
+		if g.gesture == nil {
+			// fingers changed, measure from here.
+			for _, s := range g.slots {
+				s.start = s.pos
+			}
+			g.armLongPress()
+		}
+
+		if g.ConsumeFingers > 0 && len(g.slots) >= g.ConsumeFingers {
+			g.consumed = true
+		}
+		consumed = g.consumed
+		s.sent = !consumed
+	case TouchMotion:
+		s, ok := g.slots[slot]
+		if !ok {
+			break
+		}
+
+		s.pos = *pos
+		if distance(s.start, s.pos) > float64(g.TapDistance) {
+			g.moved = true
+		}
+		consumed = g.consumed || !s.sent
+	case TouchFrame:
+		// fingers moving at once are only measured together.
+		if len(g.slots) > 0 {
+			g.motion()
+		}
+
+		// the frame completes the events sent to clients.
+		consumed = !g.passed
+		g.passed = false
+		return consumed
+	case TouchUp:
+		s, ok := g.slots[slot]
+		if !ok {
+			break
+		}
+
+		if g.gesture == nil && len(g.slots) == 1 && g.tap(time) {
+			tap := Gesture{Type: GestureTap, Phase: GestureEnd, Fingers: g.fingers, View: g.view, Center: g.tapCenter(), Scale: 1}
+			if g.report(tap) {
+				g.consumed = true
+			}
+		}
+
+		if g.gesture != nil && g.gesture.Phase != GestureEnd {
+			g.end(GestureEnd)
+		}
+		delete(g.slots, slot)
+		consumed = !s.sent
+
+		if len(g.slots) == 0 {
+			g.reset()
+		}
+	case TouchCancel:
+		if g.gesture != nil && g.gesture.Phase != GestureEnd {
+			g.end(GestureCancel)
+		}
+		g.slots = make(map[int32]*touchSlot)
+		g.reset()
+		consumed = false
+	}
+
+	if !consumed {
+		g.passed = true
+	}
+	return consumed
+}
+
+// tap checks if the touch sequence is a tap.
+func (g *GestureRecognizer) tap(now uint32) bool {
+	return !g.moved && now-g.start <= uint32(g.TapTime/time.Millisecond)
+}
+
+// tapCenter gets the center of the down positions of all fingers of the
+// touch sequence.
+func (g *GestureRecognizer) tapCenter() Point {
+	if g.ndowns == 0 {
+		return PointZero
+	}
+	return Point{X: int32(g.downs[0] / g.ndowns), Y: int32(g.downs[1] / g.ndowns)}
+}
+
+// motion updates the gesture in progress or recognizes a new one.
+func (g *GestureRecognizer) motion() {
+	if g.gesture != nil {
+		if g.gesture.Phase == GestureEnd {
+			return
+		}
+
+		gesture := g.measure(g.gesture.Type)
+		gesture.Phase = GestureUpdate
+		g.gesture = &gesture
+		g.report(gesture)
+		return
+	}
+
+	if len(g.slots) >= 2 {
+		pinch := g.measure(GesturePinch)
+		if math.Abs(pinch.Scale-1) >= g.PinchScale || math.Abs(pinch.Rotation) >= g.PinchRotation {
+			g.begin(pinch)
+			return
+		}
+	}
+
+	swipe := g.measure(GestureSwipe)
+	if math.Hypot(float64(swipe.Delta.X), float64(swipe.Delta.Y)) >= float64(g.SwipeDistance) {
+		g.begin(swipe)
+	}
+}
+
+func (g *GestureRecognizer) begin(gesture Gesture) {
+	g.disarmLongPress()
+	gesture.Phase = GestureBegin
+	g.gesture = &gesture
+	if g.report(gesture) {
+		g.consumed = true
+	}
+}
+
+func (g *GestureRecognizer) end(phase GesturePhase) {
+	gesture := *g.gesture
+	if phase == GestureEnd {
+		gesture = g.measure(gesture.Type)
+	}
+	gesture.Phase = phase
+	g.gesture = &gesture
+	g.report(gesture)
+}
+
+// measure measures the fingers as a gesture of type typ.
+func (g *GestureRecognizer) measure(typ GestureType) Gesture {
+	gesture := Gesture{
+		Type:    typ,
+		Fingers: len(g.slots),
+		View:    g.view,
+		Center:  g.center(),
+		Scale:   1,
+	}
+
+	var start, cur [2]float64
+	for _, s := range g.slots {
+		start[0] += float64(s.start.X)
+		start[1] += float64(s.start.Y)
+		cur[0] += float64(s.pos.X)
+		cur[1] += float64(s.pos.Y)
+	}
+	n := float64(len(g.slots))
+	if n == 0 {
+		return gesture
+	}
+
+	gesture.Delta = Point{
+		X: int32((cur[0] - start[0]) / n),
+		Y: int32((cur[1] - start[1]) / n),
+	}
+
+	dx, dy := gesture.Delta.X, gesture.Delta.Y
+	switch {
+	case abs32(dx) >= abs32(dy) && dx < 0:
+		gesture.Direction = SwipeLeft
+	case abs32(dx) >= abs32(dy):
+		gesture.Direction = SwipeRight
+	case dy < 0:
+		gesture.Direction = SwipeUp
+	default:
+		gesture.Direction = SwipeDown
+	}
+
+	if len(g.slots) < 2 {
+		return gesture
+	}
+
+	// scale is the change of the mean distance to the center, rotation the
+	// mean change of the angle to the center.
+	startCenter := Point{X: int32(start[0] / n), Y: int32(start[1] / n)}
+	curCenter := Point{X: int32(cur[0] / n), Y: int32(cur[1] / n)}
+	var startSpread, curSpread, rotation float64
+	for _, s := range g.slots {
+		startSpread += distance(startCenter, s.start)
+		curSpread += distance(curCenter, s.pos)
+
+		a := math.Atan2(float64(s.start.Y-startCenter.Y), float64(s.start.X-startCenter.X))
+		b := math.Atan2(float64(s.pos.Y-curCenter.Y), float64(s.pos.X-curCenter.X))
+		d := b - a
+		for d > math.Pi {
+			d -= 2 * math.Pi
+		}
+		for d < -math.Pi {
+			d += 2 * math.Pi
+		}
+		rotation += d
+	}
+
+	if startSpread > 0 {
+		gesture.Scale = curSpread / startSpread
+	}
+	gesture.Rotation = rotation / n * 180 / math.Pi
+
+	return gesture
+}
+
+// center gets the center of the start positions of the fingers.
+func (g *GestureRecognizer) center() Point {
+	var x, y int64
+	for _, s := range g.slots {
+		x += int64(s.start.X)
+		y += int64(s.start.Y)
+	}
+
+	n := int64(len(g.slots))
+	if n == 0 {
+		return PointZero
+	}
+	return Point{X: int32(x / n), Y: int32(y / n)}
+}
+
+func (g *GestureRecognizer) report(gesture Gesture) bool {
+	if g.Handler == nil {
+		return false
+	}
+	return g.Handler(gesture)
+}
+
+func (g *GestureRecognizer) armLongPress() {
+	if g.LongPressTime <= 0 {
+		return
+	}
+
+	if g.timer == nil {
+		g.timer = EventLoopAddTimer(func(interface{}) {
+			g.longPress()
+		}, nil)
+		if g.timer == nil {
+			return
+		}
+	}
+
+	EventSourceTimerUpdate(g.timer, int32(g.LongPressTime/time.Millisecond))
+}
+
+func (g *GestureRecognizer) disarmLongPress() {
+	if g.timer != nil {
+		EventSourceTimerUpdate(g.timer, 0)
+	}
+}
+
+func (g *GestureRecognizer) longPress() {
+	if g.gesture != nil || len(g.slots) == 0 || g.moved {
+		return
+	}
+
+	gesture := Gesture{Type: GestureLongPress, Phase: GestureEnd, Fingers: len(g.slots), View: g.view, Center: g.center(), Scale: 1}
+	g.gesture = &gesture
+	if g.report(gesture) {
+		g.consumed = true
+	}
+}
+
+func (g *GestureRecognizer) reset() {
+	g.disarmLongPress()
+	g.gesture = nil
+	g.consumed = false
+	g.view = 0
+	g.fingers = 0
+	g.moved = false
+	g.downs = [2]int64{}
+	g.ndowns = 0
+}
+
+func distance(a, b Point) float64 {
+	return math.Hypot(float64(b.X-a.X), float64(b.Y-a.Y))
+}
+
+func abs32(v int32) int32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,180 @@
+package wlc
+
+import "testing"
+
+// touchEvent is a touch event fed to a GestureRecognizer.
+type touchEvent struct {
+	touch    TouchType
+	slot     int32
+	pos      Point
+	time     uint32
+	consumed bool
+}
+
+func newTestGestureRecognizer(accept func(Gesture) bool) (*GestureRecognizer, *[]Gesture) {
+	var gestures []Gesture
+	g := NewGestureRecognizer(func(gesture Gesture) bool {
+		gestures = append(gestures, gesture)
+		return accept(gesture)
+	})
+	g.LongPressTime = 0
+	return g, &gestures
+}
+
+func feedTouch(t *testing.T, g *GestureRecognizer, events []touchEvent) {
+	for i, e := range events {
+		pos := e.pos
+		if consumed := g.Touch(0, e.time, Modifiers{}, e.touch, e.slot, &pos); consumed != e.consumed {
+			t.Errorf("event %d: expected consumed %t, got %t", i, e.consumed, consumed)
+		}
+	}
+}
+
+func TestGestureTap(t *testing.T) {
+	for _, accept := range []bool{false, true} {
+		g, gestures := newTestGestureRecognizer(func(Gesture) bool { return accept })
+
+		feedTouch(t, g, []touchEvent{
+			{touch: TouchDown, slot: 0, pos: Point{X: 10, Y: 10}},
+			{touch: TouchDown, slot: 1, pos: Point{X: 50, Y: 10}, time: 10},
+			{touch: TouchFrame},
+			{touch: TouchUp, slot: 0, time: 50},
+			{touch: TouchFrame},
+			// the downs were sent, so the ups are too.
+			{touch: TouchUp, slot: 1, time: 60},
+			{touch: TouchFrame},
+		})
+
+		if len(*gestures) != 1 {
+			t.Fatalf("expected 1 gesture, got %+v", *gestures)
+		}
+
+		if tap := (*gestures)[0]; tap.Type != GestureTap || tap.Fingers != 2 || tap.Center != (Point{X: 30, Y: 10}) {
+			t.Errorf("expected 2 finger tap at (30, 10), got %+v", tap)
+		}
+	}
+}
+
+func TestGestureTapMovedFinger(t *testing.T) {
+	g, gestures := newTestGestureRecognizer(func(Gesture) bool { return false })
+
+	feedTouch(t, g, []touchEvent{
+		{touch: TouchDown, slot: 0, pos: Point{X: 10, Y: 10}},
+		{touch: TouchDown, slot: 1, pos: Point{X: 50, Y: 10}, time: 10},
+		{touch: TouchFrame},
+		// the first finger slides and lifts before the second.
+		{touch: TouchMotion, slot: 0, pos: Point{X: 10, Y: 40}, time: 20},
+		{touch: TouchFrame},
+		{touch: TouchUp, slot: 0, time: 50},
+		{touch: TouchFrame},
+		{touch: TouchUp, slot: 1, time: 60},
+		{touch: TouchFrame},
+	})
+
+	for _, gesture := range *gestures {
+		if gesture.Type == GestureTap {
+			t.Errorf("expected no tap, got %+v", gesture)
+		}
+	}
+}
+
+func TestGestureTapConsumed(t *testing.T) {
+	g, gestures := newTestGestureRecognizer(func(gesture Gesture) bool {
+		return gesture.Type == GestureTap
+	})
+	g.ConsumeFingers = 1
+
+	feedTouch(t, g, []touchEvent{
+		{touch: TouchDown, slot: 0, pos: Point{X: 10, Y: 10}, consumed: true},
+		{touch: TouchFrame, consumed: true},
+		{touch: TouchUp, slot: 0, time: 50, consumed: true},
+		{touch: TouchFrame, consumed: true},
+	})
+
+	if len(*gestures) != 1 || (*gestures)[0].Type != GestureTap {
+		t.Errorf("expected a tap, got %+v", *gestures)
+	}
+}
+
+func TestGestureSwipe(t *testing.T) {
+	g, gestures := newTestGestureRecognizer(func(gesture Gesture) bool {
+		return gesture.Fingers == 3
+	})
+
+	var events []touchEvent
+	for i := int32(0); i < 3; i++ {
+		events = append(events, touchEvent{touch: TouchDown, slot: i, pos: Point{X: 500 + i*50, Y: 300}})
+	}
+	events = append(events, touchEvent{touch: TouchFrame})
+
+	for x := int32(20); x <= 200; x += 20 {
+		// consumed once the swipe is accepted by the frame at 100.
+		consumed := x > 100
+		for i := int32(0); i < 3; i++ {
+			events = append(events, touchEvent{touch: TouchMotion, slot: i, pos: Point{X: 500 + i*50 - x, Y: 300}, consumed: consumed})
+		}
+		events = append(events, touchEvent{touch: TouchFrame, consumed: consumed})
+	}
+
+	// the downs were sent before the swipe was recognized, so the ups are
+	// sent as well.
+	for i := int32(0); i < 3; i++ {
+		events = append(events, touchEvent{touch: TouchUp, slot: i})
+	}
+	events = append(events, touchEvent{touch: TouchFrame})
+
+	feedTouch(t, g, events)
+
+	if len(*gestures) < 2 {
+		t.Fatalf("expected swipe begin and end, got %+v", *gestures)
+	}
+
+	begin, end := (*gestures)[0], (*gestures)[len(*gestures)-1]
+	if begin.Type != GestureSwipe || begin.Phase != GestureBegin || begin.Direction != SwipeLeft {
+		t.Errorf("expected swipe left to begin, got %+v", begin)
+	}
+
+	if end.Phase != GestureEnd || end.Fingers != 3 || end.Delta.X != -200 {
+		t.Errorf("expected 3 finger swipe of -200 to end, got %+v", end)
+	}
+}
+
+func TestGesturePinch(t *testing.T) {
+	g, gestures := newTestGestureRecognizer(func(Gesture) bool { return false })
+
+	feedTouch(t, g, []touchEvent{
+		{touch: TouchDown, slot: 0, pos: Point{X: 100, Y: 100}},
+		{touch: TouchDown, slot: 1, pos: Point{X: 200, Y: 100}},
+		{touch: TouchFrame},
+		{touch: TouchMotion, slot: 0, pos: Point{X: 80, Y: 100}},
+		{touch: TouchMotion, slot: 1, pos: Point{X: 220, Y: 100}},
+		{touch: TouchFrame},
+	})
+
+	if len(*gestures) != 1 {
+		t.Fatalf("expected pinch to begin, got %+v", *gestures)
+	}
+
+	if pinch := (*gestures)[0]; pinch.Type != GesturePinch || pinch.Phase != GestureBegin || pinch.Scale < 1.2 {
+		t.Errorf("expected pinch out to begin, got %+v", pinch)
+	}
+}
+
+func TestGestureCancel(t *testing.T) {
+	g, gestures := newTestGestureRecognizer(func(Gesture) bool { return true })
+
+	feedTouch(t, g, []touchEvent{
+		{touch: TouchDown, slot: 0, pos: Point{X: 100, Y: 100}},
+		{touch: TouchFrame},
+		{touch: TouchMotion, slot: 0, pos: Point{X: 100, Y: 300}},
+		{touch: TouchFrame},
+		{touch: TouchMotion, slot: 0, pos: Point{X: 100, Y: 350}, consumed: true},
+		{touch: TouchFrame, consumed: true},
+		// cancel is always sent to clients.
+		{touch: TouchCancel},
+	})
+
+	if n := len(*gestures); n < 2 || (*gestures)[n-1].Phase != GestureCancel {
+		t.Errorf("expected swipe to be cancelled, got %+v", *gestures)
+	}
+}
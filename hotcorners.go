@@ -0,0 +1,269 @@
+package wlc
+
+import "time"
+
+// ScreenRegion is a corner or an edge of an output.
+type ScreenRegion int
+
+const (
+	CornerTopLeft ScreenRegion = iota
+	CornerTopRight
+	CornerBottomLeft
+	CornerBottomRight
+	EdgeTop
+	EdgeBottom
+	EdgeLeft
+	EdgeRight
+)
+
+// HotCorners runs actions when the pointer dwells in a corner of an output
+// for Dwell, or pushes against an edge of an output for Pressure pixels.
+// Actions are run once, and again only after the pointer left the corner or
+// edge. Positions are relative to the virtual resolution of the focused
+// output. Pressure is the distance positions are beyond the edge, which the
+// pointer is clamped to. When wlc already clamped the position, every motion
+// event not moving the pointer at an edge counts as one pixel.
+//
+// Call PointerMotion from the pointer motion callback and OutputDestroyed
+// from the output destroyed callback. Dwell uses a timer of the wlc event
+// loop, so corners only work after Init.
+type HotCorners struct {
+	// Dwell is the time the pointer must stay in a corner.
+	Dwell time.Duration
+	// CornerSize is the size of the corners in pixels.
+	CornerSize int32
+	// Pressure is the distance the pointer must be pushed against an edge.
+	Pressure int32
+
+	actions       map[ScreenRegion]func(Output)
+	outputActions map[Output]map[ScreenRegion]func(Output)
+
+	output Output
+	last   Point
+	// corner the pointer is in, and if its action was run.
+	corner   ScreenRegion
+	inCorner bool
+	fired    bool
+	// edge the pointer pushes against and how hard.
+	edge     ScreenRegion
+	atEdge   bool
+	pressure int32
+	timer    EventSource
+}
+
+// NewHotCorners initializes hot corners with a dwell time of 300 ms, corners
+// of 2 pixels and a pressure of 100 pixels.
+func NewHotCorners() *HotCorners {
+	return &HotCorners{
+		Dwell:         300 * time.Millisecond,
+		CornerSize:    2,
+		Pressure:      100,
+		actions:       make(map[ScreenRegion]func(Output)),
+		outputActions: make(map[Output]map[ScreenRegion]func(Output)),
+	}
+}
+
+// Set sets the action of region on all outputs. nil removes the action.
+func (h *HotCorners) Set(region ScreenRegion, action func(output Output)) {
+	if action == nil {
+		delete(h.actions, region)
+		return
+	}
+	h.actions[region] = action
+}
+
+// SetOutput sets the action of region on output, overriding the action set
+// for all outputs. nil removes the action.
+func (h *HotCorners) SetOutput(output Output, region ScreenRegion, action func(output Output)) {
+	actions, ok := h.outputActions[output]
+	if !ok {
+		if action == nil {
+			return
+		}
+		actions = make(map[ScreenRegion]func(Output))
+		h.outputActions[output] = actions
+	}
+
+	if action == nil {
+		delete(actions, region)
+		return
+	}
+	actions[region] = action
+}
+
+// PointerMotion tracks the pointer in corners and against edges. Always
+// returns false so the event is passed on to clients.
+func (h *HotCorners) PointerMotion(view View, time uint32, pos *Point) bool {
+	output := GetFocusedOutput()
+	var res Size
+	if output != 0 {
+		res = outputSize(output)
+	}
+
+	h.motion(output, res, *pos)
+	return false
+}
+
+// motion tracks the pointer at pos on output with resolution res.
+func (h *HotCorners) motion(output Output, res Size, pos Point) {
+	clamped := clampPoint(pos, Geometry{Size: res})
+	if output != h.output {
+		h.leave()
+		h.output = output
+		h.last = clamped
+	}
+
+	if output == 0 || res.W == 0 || res.H == 0 {
+		return
+	}
+
+	// last is the position the pointer was actually at.
+	last := h.last
+	h.last = clamped
+
+	if corner, ok := h.cornerAt(pos, res); ok {
+		h.atEdge = false
+		h.pressure = 0
+		if !h.inCorner || corner != h.corner {
+			h.inCorner = true
+			h.corner = corner
+			h.fired = false
+			h.armDwell()
+		}
+		return
+	}
+
+	if h.inCorner {
+		h.inCorner = false
+		h.disarmDwell()
+	}
+
+	edge, push, ok := h.edgeAt(pos, last, res)
+	if !ok {
+		h.atEdge = false
+		h.pressure = 0
+		return
+	}
+
+	if !h.atEdge || edge != h.edge {
+		h.atEdge = true
+		h.edge = edge
+		h.pressure = 0
+		h.fired = false
+	}
+
+	if push > 0 && !h.fired {
+		h.pressure += push
+		if h.pressure >= h.Pressure {
+			h.fired = true
+			h.run(edge)
+		}
+	}
+}
+
+// OutputDestroyed forgets the actions of output.
+func (h *HotCorners) OutputDestroyed(output Output) {
+	delete(h.outputActions, output)
+	if output == h.output {
+		h.leave()
+		h.output = 0
+	}
+}
+
+// cornerAt gets the corner pos is in.
+func (h *HotCorners) cornerAt(pos Point, res Size) (ScreenRegion, bool) {
+	size := h.CornerSize
+	left := pos.X < size
+	right := pos.X >= int32(res.W)-size
+	top := pos.Y < size
+	bottom := pos.Y >= int32(res.H)-size
+
+	switch {
+	case top && left:
+		return CornerTopLeft, true
+	case top && right:
+		return CornerTopRight, true
+	case bottom && left:
+		return CornerBottomLeft, true
+	case bottom && right:
+		return CornerBottomRight, true
+	}
+	return 0, false
+}
+
+// edgeAt gets the edge pos is on or beyond, and how far the pointer was
+// pushed against it. last is the clamped position of the previous motion.
+func (h *HotCorners) edgeAt(pos, last Point, res Size) (ScreenRegion, int32, bool) {
+	var edge ScreenRegion
+	var push int32
+	switch {
+	case pos.X <= 0:
+		edge, push = EdgeLeft, -pos.X
+	case pos.X >= int32(res.W)-1:
+		edge, push = EdgeRight, pos.X-(int32(res.W)-1)
+	case pos.Y <= 0:
+		edge, push = EdgeTop, -pos.Y
+	case pos.Y >= int32(res.H)-1:
+		edge, push = EdgeBottom, pos.Y-(int32(res.H)-1)
+	default:
+		return 0, 0, false
+	}
+
+	// a motion not moving the pointer was clamped by wlc.
+	if push == 0 && pos == last {
+		push = 1
+	}
+	return edge, push, true
+}
+
+func (h *HotCorners) action(region ScreenRegion) func(Output) {
+	if action, ok := h.outputActions[h.output][region]; ok {
+		return action
+	}
+	return h.actions[region]
+}
+
+func (h *HotCorners) run(region ScreenRegion) {
+	if action := h.action(region); action != nil {
+		action(h.output)
+	}
+}
+
+func (h *HotCorners) armDwell() {
+	if h.Dwell <= 0 {
+		h.fired = true
+		h.run(h.corner)
+		return
+	}
+
+	if h.timer == nil {
+		h.timer = EventLoopAddTimer(func(interface{}) {
+			if h.inCorner && !h.fired {
+				h.fired = true
+				h.run(h.corner)
+			}
+		}, nil)
+		if h.timer == nil {
+			return
+		}
+	}
+
+	EventSourceTimerUpdate(h.timer, int32(h.Dwell/time.Millisecond))
+}
+
+func (h *HotCorners) disarmDwell() {
+	if h.timer != nil {
+		EventSourceTimerUpdate(h.timer, 0)
+	}
+}
+
+// leave resets the state when the pointer leaves the output.
+func (h *HotCorners) leave() {
+	if h.inCorner {
+		h.disarmDwell()
+	}
+	h.inCorner = false
+	h.atEdge = false
+	h.pressure = 0
+	h.fired = false
+}
@@ -0,0 +1,89 @@
+package wlc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newTestHotCorners() (*HotCorners, *[]ScreenRegion) {
+	var fired []ScreenRegion
+	h := NewHotCorners()
+	h.Dwell = 0
+	h.Pressure = 20
+	for _, region := range []ScreenRegion{CornerTopLeft, EdgeLeft, EdgeRight} {
+		region := region
+		h.Set(region, func(Output) { fired = append(fired, region) })
+	}
+	return h, &fired
+}
+
+func TestHotCornersCorner(t *testing.T) {
+	h, fired := newTestHotCorners()
+	res := Size{W: 100, H: 100}
+
+	for _, pos := range []Point{{X: 50, Y: 50}, {X: 1, Y: 1}, {X: 0, Y: 0}, {X: 50, Y: 50}, {X: 0, Y: 0}} {
+		h.motion(1, res, pos)
+	}
+
+	if exp := []ScreenRegion{CornerTopLeft, CornerTopLeft}; !reflect.DeepEqual(*fired, exp) {
+		t.Errorf("expected %v, got %v", exp, *fired)
+	}
+}
+
+func TestHotCornersEdge(t *testing.T) {
+	res := Size{W: 100, H: 100}
+
+	for _, tc := range []struct {
+		name      string
+		positions []Point
+		exp       []ScreenRegion
+	}{
+		{
+			name:      "unclamped",
+			positions: []Point{{X: 50, Y: 50}, {X: 99, Y: 50}, {X: 109, Y: 50}, {X: 109, Y: 50}},
+			exp:       []ScreenRegion{EdgeRight},
+		},
+		{
+			name:      "unclamped not enough",
+			positions: []Point{{X: 50, Y: 50}, {X: 99, Y: 50}, {X: 109, Y: 50}, {X: 98, Y: 50}, {X: 107, Y: 50}},
+			exp:       nil,
+		},
+		{
+			name:      "clamped",
+			positions: append([]Point{{X: 50, Y: 50}, {X: 10, Y: 50}, {X: 0, Y: 50}}, repeatPoint(Point{Y: 50}, 20)...),
+			exp:       []ScreenRegion{EdgeLeft},
+		},
+		{
+			name:      "clamped not enough",
+			positions: append([]Point{{X: 50, Y: 50}, {X: 10, Y: 50}, {X: 0, Y: 50}}, repeatPoint(Point{Y: 50}, 19)...),
+			exp:       nil,
+		},
+		{
+			name:      "clamped sliding along edge",
+			positions: []Point{{X: 50, Y: 50}, {X: 10, Y: 50}, {X: 0, Y: 50}, {X: 0, Y: 40}, {X: 0, Y: 30}, {X: 0, Y: 20}},
+			exp:       nil,
+		},
+		{
+			name:      "leaving edge",
+			positions: []Point{{X: 50, Y: 50}, {X: 99, Y: 50}, {X: 110, Y: 50}, {X: 50, Y: 50}, {X: 99, Y: 50}, {X: 110, Y: 50}},
+			exp:       nil,
+		},
+	} {
+		h, fired := newTestHotCorners()
+		for _, pos := range tc.positions {
+			h.motion(1, res, pos)
+		}
+
+		if !reflect.DeepEqual(*fired, tc.exp) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.exp, *fired)
+		}
+	}
+}
+
+func repeatPoint(pos Point, n int) []Point {
+	positions := make([]Point, n)
+	for i := range positions {
+		positions[i] = pos
+	}
+	return positions
+}
@@ -0,0 +1,149 @@
+package wlc
+
+// Hotplug moves views off outputs that are destroyed and back when an output
+// with the same name is created again. Geometry of moved views is scaled to
+// keep its position and size relative to the output, and restored when moved
+// back. The visibility bitmask of the views, and thereby their workspace, is
+// kept.
+//
+// Call OutputCreated, OutputDestroyed and ViewDestroyed from the respective
+// callbacks.
+type Hotplug struct {
+	// Fallback chooses the output the views of a destroyed output are moved
+	// to. If nil the focused output, or the first other output, is used.
+	Fallback func(destroyed Output) Output
+
+	// migrated views keyed by the name of the output they came from.
+	migrated map[string][]migratedView
+}
+
+type migratedView struct {
+	view     View
+	mask     uint32
+	geometry Geometry
+}
+
+// NewHotplug initializes a hotplug manager.
+func NewHotplug() *Hotplug {
+	return &Hotplug{
+		migrated: make(map[string][]migratedView),
+	}
+}
+
+// Migrated gets the views moved away from the output named name.
+func (h *Hotplug) Migrated(name string) []View {
+	views := make([]View, 0, len(h.migrated[name]))
+	for _, m := range h.migrated[name] {
+		views = append(views, m.view)
+	}
+	return views
+}
+
+// OutputCreated moves views back that were moved away from an output with the
+// same name as output. Always returns true so it can be used directly as
+// output created callback.
+func (h *Hotplug) OutputCreated(output Output) bool {
+	name := output.Name()
+	for _, m := range h.migrated[name] {
+		m.view.SetOutput(output)
+		setViewMask(m.view, m.mask)
+		m.view.SetGeometry(0, m.geometry)
+	}
+	delete(h.migrated, name)
+	return true
+}
+
+// OutputDestroyed moves the views of output to the fallback output and
+// remembers where they came from.
+func (h *Hotplug) OutputDestroyed(output Output) {
+	views := output.GetViews()
+	if len(views) == 0 {
+		return
+	}
+
+	name := output.Name()
+	fallback := h.fallback(output)
+	from := output.GetVirtualResolution()
+	var to *Size
+	if fallback != 0 {
+		to = fallback.GetVirtualResolution()
+	}
+
+	for _, view := range views {
+		g := view.GetGeometry()
+		if g == nil {
+			continue
+		}
+
+		m := migratedView{view: view, mask: viewMask(view), geometry: *g}
+		h.migrated[name] = append(h.migrated[name], m)
+		if fallback == 0 {
+			continue
+		}
+
+		view.SetOutput(fallback)
+		setViewMask(view, m.mask)
+		if from != nil && to != nil {
+			view.SetGeometry(0, scaleGeometry(m.geometry, *from, *to))
+		}
+	}
+}
+
+// ViewDestroyed forgets view.
+func (h *Hotplug) ViewDestroyed(view View) {
+	for name, views := range h.migrated {
+		for i, m := range views {
+			if m.view == view {
+				views = append(views[:i], views[i+1:]...)
+				break
+			}
+		}
+
+		if len(views) == 0 {
+			delete(h.migrated, name)
+		} else {
+			h.migrated[name] = views
+		}
+	}
+}
+
+func (h *Hotplug) fallback(destroyed Output) Output {
+	if h.Fallback != nil {
+		return h.Fallback(destroyed)
+	}
+
+	if focused := GetFocusedOutput(); focused != 0 && focused != destroyed {
+		return focused
+	}
+
+	for _, output := range GetOutputs() {
+		if output != destroyed {
+			return output
+		}
+	}
+
+	return 0
+}
+
+// scaleGeometry scales geometry from an output of resolution from to an
+// output of resolution to.
+func scaleGeometry(geometry Geometry, from, to Size) Geometry {
+	if from.W == 0 || from.H == 0 {
+		return geometry
+	}
+
+	scale := func(v int64, to, from uint32) int64 {
+		return v * int64(to) / int64(from)
+	}
+
+	return Geometry{
+		Origin: Point{
+			X: int32(scale(int64(geometry.Origin.X), to.W, from.W)),
+			Y: int32(scale(int64(geometry.Origin.Y), to.H, from.H)),
+		},
+		Size: Size{
+			W: uint32(scale(int64(geometry.Size.W), to.W, from.W)),
+			H: uint32(scale(int64(geometry.Size.H), to.H, from.H)),
+		},
+	}
+}
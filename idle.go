@@ -0,0 +1,212 @@
+package wlc
+
+import "time"
+
+// Idle triggers actions when there has been no input for a while. Every
+// timeout has an idle action, run when the timeout expires, and a resume
+// action, run on the first input after that. Inhibitors can prevent timeouts
+// from expiring, e.g. while a video is played fullscreen.
+//
+// Call the input methods (KeyboardKey, PointerButton, PointerScroll,
+// PointerMotion and Touch) from the respective callbacks. Timers are added to
+// the wlc event loop, so Start must be called after Init, e.g. from the
+// compositor ready callback.
+type Idle struct {
+	timeouts   []*idleTimeout
+	inhibitors []func() bool
+	tokens     map[*IdleInhibitToken]bool
+	started    bool
+}
+
+type idleTimeout struct {
+	timeout time.Duration
+	idle    func()
+	resume  func()
+	source  EventSource
+	fired   bool
+}
+
+// IdleInhibitToken is an explicit inhibitor, held e.g. on behalf of a client
+// or an IPC command. Idle timeouts don't expire while a token is held.
+type IdleInhibitToken struct {
+	idle *Idle
+}
+
+// NewIdle initializes an idle manager.
+func NewIdle() *Idle {
+	return &Idle{
+		tokens: make(map[*IdleInhibitToken]bool),
+	}
+}
+
+// AddTimeout adds a timeout running idle after timeout without input and
+// resume on the next input. Either action can be nil.
+func (i *Idle) AddTimeout(timeout time.Duration, idle, resume func()) {
+	t := &idleTimeout{timeout: timeout, idle: idle, resume: resume}
+	i.timeouts = append(i.timeouts, t)
+	if i.started {
+		i.arm(t)
+	}
+}
+
+// AddSleepTimeout adds a timeout putting all outputs to sleep, waking them up
+// again on input.
+func (i *Idle) AddSleepTimeout(timeout time.Duration) {
+	setSleep := func(sleep bool) func() {
+		return func() {
+			for _, output := range GetOutputs() {
+				output.SetSleep(sleep)
+			}
+		}
+	}
+	i.AddTimeout(timeout, setSleep(true), setSleep(false))
+}
+
+// AddInhibitor adds a condition preventing timeouts from expiring while it
+// returns true. Conditions are checked when a timeout would expire.
+func (i *Idle) AddInhibitor(inhibited func() bool) {
+	i.inhibitors = append(i.inhibitors, inhibited)
+}
+
+// Inhibit gets a token preventing timeouts from expiring until it is
+// released.
+func (i *Idle) Inhibit() *IdleInhibitToken {
+	token := &IdleInhibitToken{idle: i}
+	i.tokens[token] = true
+	return token
+}
+
+// Release releases the token. The timeouts start over from the time of
+// release.
+func (t *IdleInhibitToken) Release() {
+	if t.idle.tokens[t] {
+		delete(t.idle.tokens, t)
+		t.idle.Activity()
+	}
+}
+
+// Inhibited checks if timeouts are currently inhibited.
+func (i *Idle) Inhibited() bool {
+	if len(i.tokens) > 0 {
+		return true
+	}
+
+	for _, inhibited := range i.inhibitors {
+		if inhibited() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Start starts the timers of all timeouts.
+func (i *Idle) Start() {
+	if i.started {
+		return
+	}
+
+	i.started = true
+	for _, t := range i.timeouts {
+		i.arm(t)
+	}
+}
+
+// Stop removes the timers of all timeouts from the event loop.
+func (i *Idle) Stop() {
+	i.started = false
+	for _, t := range i.timeouts {
+		if t.source != nil {
+			EventSourceRemove(t.source)
+			t.source = nil
+		}
+	}
+}
+
+// Activity resumes from expired timeouts and restarts all timers. It's called
+// by the input methods.
+func (i *Idle) Activity() {
+	for _, t := range i.timeouts {
+		if t.fired {
+			t.fired = false
+			if t.resume != nil {
+				t.resume()
+			}
+		}
+
+		if i.started {
+			i.arm(t)
+		}
+	}
+}
+
+// KeyboardKey registers activity. Always returns false so the event is passed
+// on to clients.
+func (i *Idle) KeyboardKey(view View, time uint32, modifiers Modifiers, key uint32, state KeyState) bool {
+	i.Activity()
+	return false
+}
+
+// PointerButton registers activity. Always returns false so the event is
+// passed on to clients.
+func (i *Idle) PointerButton(view View, time uint32, modifiers Modifiers, button uint32, state ButtonState, pos *Point) bool {
+	i.Activity()
+	return false
+}
+
+// PointerScroll registers activity. Always returns false so the event is
+// passed on to clients.
+func (i *Idle) PointerScroll(view View, time uint32, modifiers Modifiers, axis uint8, amount [2]float64) bool {
+	i.Activity()
+	return false
+}
+
+// PointerMotion registers activity. Always returns false so the event is
+// passed on to clients.
+func (i *Idle) PointerMotion(view View, time uint32, pos *Point) bool {
+	i.Activity()
+	return false
+}
+
+// Touch registers activity. Always returns false so the event is passed on
+// to clients.
+func (i *Idle) Touch(view View, time uint32, modifiers Modifiers, touch TouchType, slot int32, pos *Point) bool {
+	i.Activity()
+	return false
+}
+
+func (i *Idle) arm(t *idleTimeout) {
+	if t.source == nil {
+		t.source = EventLoopAddTimer(func(interface{}) {
+			i.expire(t)
+		}, nil)
+		if t.source == nil {
+			return
+		}
+	}
+
+	EventSourceTimerUpdate(t.source, int32(t.timeout/time.Millisecond))
+}
+
+func (i *Idle) expire(t *idleTimeout) {
+	if i.Inhibited() {
+		i.arm(t)
+		return
+	}
+
+	if !t.fired {
+		t.fired = true
+		if t.idle != nil {
+			t.idle()
+		}
+	}
+}
+
+// FullscreenInhibitor gets an inhibitor condition that is true while the
+// view focused by focus is fullscreen.
+func FullscreenInhibitor(focus *FocusManager) func() bool {
+	return func() bool {
+		view := focus.Focused()
+		return view != 0 && view.GetState()&BitFullscreen != 0
+	}
+}
@@ -2,34 +2,52 @@ package wlc
 
 /*
 #cgo LDFLAGS: -lwlc
-#include <stdlib.h>
 #include <wlc/wlc.h>
+
+// modifiers and position are passed by value to keep them off the Go heap.
+static inline uint32_t keyboard_get_keysym_for_key(uint32_t key, struct wlc_modifiers modifiers) {
+	return wlc_keyboard_get_keysym_for_key(key, &modifiers);
+}
+
+static inline uint32_t keyboard_get_utf32_for_key(uint32_t key, struct wlc_modifiers modifiers) {
+	return wlc_keyboard_get_utf32_for_key(key, &modifiers);
+}
+
+static inline void pointer_set_position(struct wlc_point position) {
+	wlc_pointer_set_position(&position);
+}
 */
 import "C"
 
 import "unsafe"
 
 // KeyboardGetXKBState exposes xkb_state. Can be used for more advanced key
-// handling. This is currently only exposed as a C struct.
-func KeyboardGetXKBState() *C.struct_xkb_state {
-	return C.wlc_keyboard_get_xkb_state()
+// handling. Returns nil if there is no keyboard state.
+func KeyboardGetXKBState() *XKBState {
+	state := C.wlc_keyboard_get_xkb_state()
+	if state == nil {
+		return nil
+	}
+	return &XKBState{state: state}
 }
 
 // KeyboardGetXKBKeymap exposes xkb_keymap. Can be used for more advanced key
-// handling. This is currently only exposed as a C struct.
-func KeyboardGetXKBKeymap() *C.struct_xkb_keymap {
-	return C.wlc_keyboard_get_xkb_keymap()
+// handling. Returns nil if there is no keymap.
+func KeyboardGetXKBKeymap() *XKBKeymap {
+	keymap := C.wlc_keyboard_get_xkb_keymap()
+	if keymap == nil {
+		return nil
+	}
+	return &XKBKeymap{keymap: keymap}
 }
 
 // KeyboardGetCurrentKeys gets currently held keys.
 func KeyboardGetCurrentKeys() []uint32 {
 	var len C.size_t
 	keys := C.wlc_keyboard_get_current_keys(&len)
-	goKeys := make([]uint32, 0, int(len))
-	size := int(unsafe.Sizeof(*keys))
-	for i := 0; i < int(len); i++ {
-		ptr := unsafe.Pointer(uintptr(unsafe.Pointer(keys)) + uintptr(size*i))
-		goKeys[i] = *(*uint32)(ptr)
+	goKeys := make([]uint32, int(len))
+	if keys != nil {
+		copy(goKeys, unsafe.Slice((*uint32)(unsafe.Pointer(keys)), int(len)))
 	}
 
 	return goKeys
@@ -39,9 +57,7 @@ func KeyboardGetCurrentKeys() []uint32 {
 // keysym. Passed modifiers may transform the key.
 func KeyboardGetKeysymForKey(key uint32, mods *Modifiers) uint32 {
 	if mods != nil {
-		cmods := mods.c()
-		defer C.free(unsafe.Pointer(cmods))
-		return uint32(C.wlc_keyboard_get_keysym_for_key(C.uint32_t(key), cmods))
+		return uint32(C.keyboard_get_keysym_for_key(C.uint32_t(key), mods.c()))
 	}
 
 	return uint32(C.wlc_keyboard_get_keysym_for_key(C.uint32_t(key), nil))
@@ -51,9 +67,7 @@ func KeyboardGetKeysymForKey(key uint32, mods *Modifiers) uint32 {
 // Unicdoe/UTF-32 codepoint. Passed modifiers may transform the key.
 func KeyboardGetUtf32ForKey(key uint32, mods *Modifiers) uint32 {
 	if mods != nil {
-		cmods := mods.c()
-		defer C.free(unsafe.Pointer(cmods))
-		return uint32(C.wlc_keyboard_get_utf32_for_key(C.uint32_t(key), cmods))
+		return uint32(C.keyboard_get_utf32_for_key(C.uint32_t(key), mods.c()))
 	}
 
 	return uint32(C.wlc_keyboard_get_utf32_for_key(C.uint32_t(key), nil))
@@ -68,7 +82,5 @@ func PointerGetPosition() *Point {
 
 // PointerSetPosition sets pointer position.
 func PointerSetPosition(pos Point) {
-	cpos := pos.c()
-	defer C.free(unsafe.Pointer(cpos))
-	C.wlc_pointer_set_position(cpos)
+	C.pointer_set_position(pos.c())
 }
@@ -0,0 +1,363 @@
+package wlc
+
+/*
+#cgo LDFLAGS: -linput
+#include <libinput.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InputCapability describes what kind of input an input device provides.
+type InputCapability uint32
+
+const (
+	BitCapKeyboard   InputCapability = 1 << 0
+	BitCapPointer                    = 1 << 1
+	BitCapTouch                      = 1 << 2
+	BitCapTabletTool                 = 1 << 3
+	BitCapTabletPad                  = 1 << 4
+	BitCapGesture                    = 1 << 5
+	BitCapSwitch                     = 1 << 6
+)
+
+// AccelProfile is the pointer acceleration profile of an input device.
+type AccelProfile C.enum_libinput_config_accel_profile
+
+const (
+	AccelProfileNone     AccelProfile = 0
+	AccelProfileFlat                  = 1 << 0
+	AccelProfileAdaptive              = 1 << 1
+)
+
+// InputDevice is a reference to struct libinput_device which is handled
+// internally by wlc. It's only valid until the input destroyed callback
+// returns.
+type InputDevice struct {
+	device *C.struct_libinput_device
+}
+
+// Name gets the name of the device, e.g. "SynPS/2 Synaptics TouchPad".
+func (d InputDevice) Name() string {
+	return goStringOrEmpty(C.libinput_device_get_name(d.device))
+}
+
+// Sysname gets the system name of the device, e.g. "event4".
+func (d InputDevice) Sysname() string {
+	return goStringOrEmpty(C.libinput_device_get_sysname(d.device))
+}
+
+// Vendor gets the vendor id of the device.
+func (d InputDevice) Vendor() uint32 {
+	return uint32(C.libinput_device_get_id_vendor(d.device))
+}
+
+// Product gets the product id of the device.
+func (d InputDevice) Product() uint32 {
+	return uint32(C.libinput_device_get_id_product(d.device))
+}
+
+// Capabilities gets the capabilities of the device.
+func (d InputDevice) Capabilities() InputCapability {
+	var caps InputCapability
+	for bit, capability := range []C.enum_libinput_device_capability{
+		C.LIBINPUT_DEVICE_CAP_KEYBOARD,
+		C.LIBINPUT_DEVICE_CAP_POINTER,
+		C.LIBINPUT_DEVICE_CAP_TOUCH,
+		C.LIBINPUT_DEVICE_CAP_TABLET_TOOL,
+		C.LIBINPUT_DEVICE_CAP_TABLET_PAD,
+		C.LIBINPUT_DEVICE_CAP_GESTURE,
+		C.LIBINPUT_DEVICE_CAP_SWITCH,
+	} {
+		if C.libinput_device_has_capability(d.device, capability) != 0 {
+			caps |= 1 << uint(bit)
+		}
+	}
+	return caps
+}
+
+// Has checks if the device has all capabilities in caps.
+func (d InputDevice) Has(caps InputCapability) bool {
+	return d.Capabilities()&caps == caps
+}
+
+// Touchpad checks if the device is a touchpad, i.e. a pointer supporting
+// tapping.
+func (d InputDevice) Touchpad() bool {
+	return d.Has(BitCapPointer) && C.libinput_device_config_tap_get_finger_count(d.device) > 0
+}
+
+// SetTap enables or disables tap-to-click.
+func (d InputDevice) SetTap(enabled bool) error {
+	if C.libinput_device_config_tap_get_finger_count(d.device) == 0 {
+		return fmt.Errorf("%s: tapping not supported", d.Name())
+	}
+
+	state := C.enum_libinput_config_tap_state(C.LIBINPUT_CONFIG_TAP_DISABLED)
+	if enabled {
+		state = C.LIBINPUT_CONFIG_TAP_ENABLED
+	}
+	return d.configError("tapping", C.libinput_device_config_tap_set_enabled(d.device, state))
+}
+
+// SetNaturalScroll enables or disables natural scrolling.
+func (d InputDevice) SetNaturalScroll(enabled bool) error {
+	if C.libinput_device_config_scroll_has_natural_scroll(d.device) == 0 {
+		return fmt.Errorf("%s: natural scrolling not supported", d.Name())
+	}
+	return d.configError("natural scrolling", C.libinput_device_config_scroll_set_natural_scroll_enabled(d.device, cBool(enabled)))
+}
+
+// SetAccelProfile sets the pointer acceleration profile.
+func (d InputDevice) SetAccelProfile(profile AccelProfile) error {
+	if C.libinput_device_config_accel_is_available(d.device) == 0 {
+		return fmt.Errorf("%s: acceleration not supported", d.Name())
+	}
+	return d.configError("acceleration profile", C.libinput_device_config_accel_set_profile(d.device, C.enum_libinput_config_accel_profile(profile)))
+}
+
+// SetAccelSpeed sets the pointer acceleration speed in the range [-1, 1].
+func (d InputDevice) SetAccelSpeed(speed float64) error {
+	if C.libinput_device_config_accel_is_available(d.device) == 0 {
+		return fmt.Errorf("%s: acceleration not supported", d.Name())
+	}
+	return d.configError("acceleration speed", C.libinput_device_config_accel_set_speed(d.device, C.double(speed)))
+}
+
+// SetLeftHanded enables or disables left-handed mode, swapping the primary
+// and secondary buttons.
+func (d InputDevice) SetLeftHanded(enabled bool) error {
+	if C.libinput_device_config_left_handed_is_available(d.device) == 0 {
+		return fmt.Errorf("%s: left-handed mode not supported", d.Name())
+	}
+	return d.configError("left-handed mode", C.libinput_device_config_left_handed_set(d.device, cBool(enabled)))
+}
+
+// SetDisableWhileTyping enables or disables disabling the device while
+// typing.
+func (d InputDevice) SetDisableWhileTyping(enabled bool) error {
+	if C.libinput_device_config_dwt_is_available(d.device) == 0 {
+		return fmt.Errorf("%s: disable-while-typing not supported", d.Name())
+	}
+
+	state := C.enum_libinput_config_dwt_state(C.LIBINPUT_CONFIG_DWT_DISABLED)
+	if enabled {
+		state = C.LIBINPUT_CONFIG_DWT_ENABLED
+	}
+	return d.configError("disable-while-typing", C.libinput_device_config_dwt_set_enabled(d.device, state))
+}
+
+func (d InputDevice) configError(what string, status C.enum_libinput_config_status) error {
+	if status == C.LIBINPUT_CONFIG_STATUS_SUCCESS {
+		return nil
+	}
+	return fmt.Errorf("%s: failed to set %s: %s", d.Name(), what, C.GoString(C.libinput_config_status_to_str(status)))
+}
+
+func cBool(b bool) C.int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// InputConfig describes the configuration of input devices matching Name and
+// Type. Settings left nil are not changed.
+type InputConfig struct {
+	// Name is a glob pattern matched against InputDevice.Name, see
+	// MatchGlob. Empty matches any device. The pattern is compiled when
+	// the config is added to InputConfigs, or on the first Match.
+	Name string
+	// Type are capabilities the device must have. 0 matches any device.
+	Type InputCapability
+	// Touchpad only matches touchpads.
+	Touchpad bool
+
+	Tap                *bool
+	NaturalScroll      *bool
+	AccelProfile       AccelProfile
+	AccelSpeed         *float64
+	LeftHanded         *bool
+	DisableWhileTyping *bool
+
+	// name is the compiled Name pattern.
+	name Matcher
+}
+
+// compile compiles the Name pattern.
+func (c *InputConfig) compile() error {
+	if c.Name == "" {
+		c.name = nil
+		return nil
+	}
+
+	name, err := compileGlob(c.Name)
+	if err != nil {
+		return err
+	}
+	c.name = name
+	return nil
+}
+
+// Match checks if the configuration applies to device.
+func (c *InputConfig) Match(device InputDevice) bool {
+	if c.Name != "" {
+		if c.name == nil {
+			c.name = MatchGlob(c.Name)
+		}
+		if !c.name.MatchString(device.Name()) {
+			return false
+		}
+	}
+
+	if c.Type != 0 && !device.Has(c.Type) {
+		return false
+	}
+
+	return !c.Touchpad || device.Touchpad()
+}
+
+// Apply applies the configuration to device. All settings are applied even
+// if some fail, the errors are combined.
+func (c *InputConfig) Apply(device InputDevice) error {
+	var errs []string
+	check := func(err error) {
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if c.Tap != nil {
+		check(device.SetTap(*c.Tap))
+	}
+	if c.NaturalScroll != nil {
+		check(device.SetNaturalScroll(*c.NaturalScroll))
+	}
+	if c.AccelProfile != AccelProfileNone {
+		check(device.SetAccelProfile(c.AccelProfile))
+	}
+	if c.AccelSpeed != nil {
+		check(device.SetAccelSpeed(*c.AccelSpeed))
+	}
+	if c.LeftHanded != nil {
+		check(device.SetLeftHanded(*c.LeftHanded))
+	}
+	if c.DisableWhileTyping != nil {
+		check(device.SetDisableWhileTyping(*c.DisableWhileTyping))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// InputConfigs applies input configurations when input devices are created.
+// Every configuration matching a device is applied, in order, so general
+// configurations, e.g. for all touchpads, should come before the ones for
+// specific devices.
+//
+// Call InputCreated and InputDestroyed from the respective callbacks.
+type InputConfigs struct {
+	// OnError is called when applying a configuration fails, if not nil.
+	OnError func(device InputDevice, err error)
+
+	configs []InputConfig
+	devices []InputDevice
+}
+
+// NewInputConfigs initializes input configurations. Returns an error if the
+// Name pattern of a configuration is invalid.
+func NewInputConfigs(configs ...InputConfig) (*InputConfigs, error) {
+	c := &InputConfigs{}
+	if err := c.Set(configs...); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Add appends config to the list of configurations. It's only applied to
+// devices created after it was added, or by calling Reload. Returns an error
+// if the Name pattern of config is invalid.
+func (c *InputConfigs) Add(config InputConfig) error {
+	if err := config.compile(); err != nil {
+		return err
+	}
+
+	c.configs = append(c.configs, config)
+	return nil
+}
+
+// Set replaces the list of configurations. The configurations are only
+// applied to existing devices by calling Reload. Returns an error if the
+// Name pattern of a configuration is invalid, the list is left unchanged in
+// that case.
+func (c *InputConfigs) Set(configs ...InputConfig) error {
+	compiled := make([]InputConfig, len(configs))
+	for i, config := range configs {
+		if err := config.compile(); err != nil {
+			return err
+		}
+		compiled[i] = config
+	}
+
+	c.configs = compiled
+	return nil
+}
+
+// Configs gets the configurations in the order they were added.
+func (c *InputConfigs) Configs() []InputConfig {
+	configs := make([]InputConfig, len(c.configs))
+	copy(configs, c.configs)
+	return configs
+}
+
+// Devices gets the input devices currently present, in creation order.
+func (c *InputConfigs) Devices() []InputDevice {
+	devices := make([]InputDevice, len(c.devices))
+	copy(devices, c.devices)
+	return devices
+}
+
+// InputCreated applies the matching configurations to device. Always returns
+// true so it can be used directly as input created callback.
+func (c *InputConfigs) InputCreated(device InputDevice) bool {
+	c.devices = append(c.devices, device)
+	c.Apply(device)
+	return true
+}
+
+// InputDestroyed forgets device.
+func (c *InputConfigs) InputDestroyed(device InputDevice) {
+	for i, d := range c.devices {
+		if d == device {
+			c.devices = append(c.devices[:i], c.devices[i+1:]...)
+			break
+		}
+	}
+}
+
+// Apply applies the matching configurations to device.
+func (c *InputConfigs) Apply(device InputDevice) {
+	for i := range c.configs {
+		config := &c.configs[i]
+		if !config.Match(device) {
+			continue
+		}
+
+		if err := config.Apply(device); err != nil && c.OnError != nil {
+			c.OnError(device, err)
+		}
+	}
+}
+
+// Reload applies the configurations to all devices again, e.g. after
+// calling Add or Set.
+func (c *InputConfigs) Reload() {
+	for _, device := range c.devices {
+		c.Apply(device)
+	}
+}
@@ -0,0 +1,35 @@
+package wlc
+
+import "testing"
+
+func TestInputConfigsAdd(t *testing.T) {
+	configs, err := NewInputConfigs(
+		InputConfig{Touchpad: true},
+		InputConfig{Name: "SynPS/2 *"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, config := range configs.Configs() {
+		if config.Name != "" && (config.name == nil || !config.name.MatchString("SynPS/2 Synaptics TouchPad")) {
+			t.Errorf("%s: expected compiled pattern", config.Name)
+		}
+	}
+
+	if err := configs.Add(InputConfig{Name: "\xff*"}); err == nil {
+		t.Error("expected invalid pattern to fail")
+	}
+
+	if err := configs.Set(InputConfig{Name: "*"}, InputConfig{Name: "\xff"}); err == nil {
+		t.Error("expected invalid pattern to fail")
+	}
+
+	if n := len(configs.Configs()); n != 2 {
+		t.Errorf("expected configs to be unchanged after errors, got %d", n)
+	}
+
+	if _, err := NewInputConfigs(InputConfig{Name: "\xff"}); err == nil {
+		t.Error("expected invalid pattern to fail")
+	}
+}
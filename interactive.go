@@ -0,0 +1,266 @@
+package wlc
+
+// Interactive is a controller for interactive move and resize of views. A
+// grab is started either from the callbacks set with SetViewRequestMoveCb and
+// SetViewRequestResizeCb, or from a binding with StartMove and StartResize.
+// Pointer motion and button events must be passed to PointerMotion and
+// PointerButton while a grab is active.
+//
+// The view geometry is always computed from the geometry at the start of the
+// grab and the total pointer motion, so min/max and aspect constraints never
+// accumulate rounding errors.
+type Interactive struct {
+	// MinSize is the minimum size of a view during resize. Zero values
+	// means a minimum of 1.
+	MinSize Size
+	// MaxSize is the maximum size of a view during resize. Zero values
+	// means unlimited.
+	MaxSize Size
+	// Aspect is the aspect ratio (width:height) kept during resize. Zero
+	// values means any aspect ratio.
+	Aspect Size
+	// RaiseView brings a view to front, e.g. Layers.Raise. If nil
+	// View.BringToFront is used.
+	RaiseView func(View)
+
+	view  View
+	grab  Point
+	start Geometry
+	edges uint32
+	state ViewStateBit
+}
+
+// NewInteractive initializes an interactive controller.
+func NewInteractive() *Interactive {
+	return &Interactive{}
+}
+
+// Active checks if a grab is active.
+func (i *Interactive) Active() bool {
+	return i.view != 0
+}
+
+// View gets the view currently grabbed. Returns 0 if no grab is active.
+func (i *Interactive) View() View {
+	return i.view
+}
+
+// Edges gets the edges of the current resize. Returns ResizeEdgeNone for
+// moves.
+func (i *Interactive) Edges() uint32 {
+	return i.edges
+}
+
+// StartMove starts an interactive move of view with the pointer at origin.
+// Returns false if a grab is already active.
+func (i *Interactive) StartMove(view View, origin Point) bool {
+	return i.begin(view, origin, 0, BitMoving)
+}
+
+// StartResize starts an interactive resize of view with the pointer at
+// origin. If edges is ResizeEdgeNone the edges are inferred from where origin
+// is relative to the center of the view. Returns false if a grab is already
+// active.
+func (i *Interactive) StartResize(view View, edges uint32, origin Point) bool {
+	if edges == 0 {
+		g := view.GetGeometry()
+		if g == nil {
+			return false
+		}
+		edges = InferResizeEdges(*g, origin)
+	}
+
+	return i.begin(view, origin, edges, BitResizing)
+}
+
+func (i *Interactive) begin(view View, origin Point, edges uint32, state ViewStateBit) bool {
+	if i.Active() || view == 0 {
+		return false
+	}
+
+	g := view.GetGeometry()
+	if g == nil {
+		return false
+	}
+
+	i.view = view
+	i.grab = origin
+	i.start = *g
+	i.edges = edges
+	i.state = state
+
+	raiseView(i.RaiseView, view)
+	view.SetState(state, true)
+	return true
+}
+
+// Stop stops the current grab.
+func (i *Interactive) Stop() {
+	if !i.Active() {
+		return
+	}
+
+	i.view.SetState(i.state, false)
+	i.view = 0
+	i.edges = 0
+}
+
+// ViewRequestMove starts an interactive move. Can be used directly as view
+// request move callback.
+func (i *Interactive) ViewRequestMove(view View, origin *Point) {
+	i.StartMove(view, *origin)
+}
+
+// ViewRequestResize starts an interactive resize. Can be used directly as
+// view request resize callback.
+func (i *Interactive) ViewRequestResize(view View, edges uint32, origin *Point) {
+	i.StartResize(view, edges, *origin)
+}
+
+// ViewDestroyed stops the grab if view is grabbed.
+func (i *Interactive) ViewDestroyed(view View) {
+	if view == i.view {
+		// the view is gone, don't touch its state.
+		i.view = 0
+		i.edges = 0
+	}
+}
+
+// PointerButton stops the current grab when a button is released. Returns
+// true if a grab was active, meaning the event should not be sent to
+// clients.
+func (i *Interactive) PointerButton(view View, time uint32, modifiers Modifiers, button uint32, state ButtonState, pos *Point) bool {
+	if !i.Active() {
+		return false
+	}
+
+	if state == ButtonStateReleased {
+		i.Stop()
+	}
+
+	return true
+}
+
+// PointerMotion updates the geometry of the grabbed view. Returns true if a
+// grab is active, meaning the event should not be sent to clients. The
+// pointer position is not updated, this must still be done with
+// PointerSetPosition.
+func (i *Interactive) PointerMotion(view View, time uint32, pos *Point) bool {
+	if !i.Active() {
+		return false
+	}
+
+	delta := Point{X: pos.X - i.grab.X, Y: pos.Y - i.grab.Y}
+	if i.edges == 0 {
+		g := i.start
+		g.Origin.X += delta.X
+		g.Origin.Y += delta.Y
+		i.view.SetGeometry(0, g)
+		return true
+	}
+
+	g := ResizeGeometry(i.start, i.edges, delta, i.MinSize, i.MaxSize, i.Aspect)
+	i.view.SetGeometry(i.edges, g)
+	return true
+}
+
+// InferResizeEdges gets the resize edges closest to point, relative to the
+// center of geometry. A point in the exact center results in
+// ResizeEdgeBottomRight.
+func InferResizeEdges(geometry Geometry, point Point) uint32 {
+	halfw := int64(geometry.Origin.X) + int64(geometry.Size.W)/2
+	halfh := int64(geometry.Origin.Y) + int64(geometry.Size.H)/2
+
+	edges := uint32(0)
+	if int64(point.X) < halfw {
+		edges |= ResizeEdgeLeft
+	} else if int64(point.X) > halfw {
+		edges |= ResizeEdgeRight
+	}
+
+	if int64(point.Y) < halfh {
+		edges |= ResizeEdgeTop
+	} else if int64(point.Y) > halfh {
+		edges |= ResizeEdgeBottom
+	}
+
+	if edges == 0 {
+		edges = ResizeEdgeBottomRight
+	}
+
+	return edges
+}
+
+// ResizeGeometry resizes geometry by moving the given edges delta. The edges
+// opposite to the moved edges stay in place. The size is kept within min and
+// max, zero values meaning no constraint, and the aspect ratio is kept if
+// aspect is not zero.
+func ResizeGeometry(geometry Geometry, edges uint32, delta Point, min, max Size, aspect Size) Geometry {
+	x, y := int64(geometry.Origin.X), int64(geometry.Origin.Y)
+	w, h := int64(geometry.Size.W), int64(geometry.Size.H)
+	right, bottom := x+w, y+h
+
+	horizontal := edges&(ResizeEdgeLeft|ResizeEdgeRight) != 0
+	vertical := edges&(ResizeEdgeTop|ResizeEdgeBottom) != 0
+
+	if edges&ResizeEdgeLeft != 0 {
+		w -= int64(delta.X)
+	} else if edges&ResizeEdgeRight != 0 {
+		w += int64(delta.X)
+	}
+
+	if edges&ResizeEdgeTop != 0 {
+		h -= int64(delta.Y)
+	} else if edges&ResizeEdgeBottom != 0 {
+		h += int64(delta.Y)
+	}
+
+	w = clampLength(w, min.W, max.W)
+	h = clampLength(h, min.H, max.H)
+
+	if aspect.W != 0 && aspect.H != 0 {
+		aw, ah := int64(aspect.W), int64(aspect.H)
+		switch {
+		case horizontal && !vertical:
+			h = w * ah / aw
+		case vertical && !horizontal:
+			w = h * aw / ah
+		case w*ah > h*aw:
+			// corner resize, the dominating dimension decides.
+			h = w * ah / aw
+		default:
+			w = h * aw / ah
+		}
+		w = clampLength(w, min.W, max.W)
+		h = clampLength(h, min.H, max.H)
+	}
+
+	if edges&ResizeEdgeLeft != 0 {
+		x = right - w
+	}
+
+	if edges&ResizeEdgeTop != 0 {
+		y = bottom - h
+	}
+
+	return Geometry{
+		Origin: Point{X: int32(x), Y: int32(y)},
+		Size:   Size{W: uint32(w), H: uint32(h)},
+	}
+}
+
+func clampLength(length int64, min, max uint32) int64 {
+	if max != 0 && length > int64(max) {
+		length = int64(max)
+	}
+
+	if length < int64(min) {
+		length = int64(min)
+	}
+
+	if length < 1 {
+		length = 1
+	}
+
+	return length
+}
@@ -0,0 +1,202 @@
+package wlc
+
+import "testing"
+
+func TestResizeGeometry(t *testing.T) {
+	geometry := Geometry{Origin: Point{X: 100, Y: 100}, Size: Size{W: 200, H: 100}}
+
+	for _, tc := range []struct {
+		name   string
+		edges  uint32
+		delta  Point
+		min    Size
+		max    Size
+		aspect Size
+		exp    Geometry
+	}{
+		{
+			name:  "right",
+			edges: ResizeEdgeRight,
+			delta: Point{X: 10, Y: 10},
+			exp:   Geometry{Origin: Point{X: 100, Y: 100}, Size: Size{W: 210, H: 100}},
+		},
+		{
+			name:  "left",
+			edges: ResizeEdgeLeft,
+			delta: Point{X: 10, Y: 10},
+			exp:   Geometry{Origin: Point{X: 110, Y: 100}, Size: Size{W: 190, H: 100}},
+		},
+		{
+			name:  "left min size",
+			edges: ResizeEdgeLeft,
+			delta: Point{X: 300},
+			min:   Size{W: 80, H: 40},
+			exp:   Geometry{Origin: Point{X: 220, Y: 100}, Size: Size{W: 80, H: 100}},
+		},
+		{
+			name:  "top left",
+			edges: ResizeEdgeTopLeft,
+			delta: Point{X: -10, Y: -20},
+			exp:   Geometry{Origin: Point{X: 90, Y: 80}, Size: Size{W: 210, H: 120}},
+		},
+		{
+			name:  "bottom right max size",
+			edges: ResizeEdgeBottomRight,
+			delta: Point{X: 1000, Y: 1000},
+			max:   Size{W: 300, H: 150},
+			exp:   Geometry{Origin: Point{X: 100, Y: 100}, Size: Size{W: 300, H: 150}},
+		},
+		{
+			name:  "top",
+			edges: ResizeEdgeTop,
+			delta: Point{Y: 50},
+			exp:   Geometry{Origin: Point{X: 100, Y: 150}, Size: Size{W: 200, H: 50}},
+		},
+		{
+			name:  "bottom at least 1 pixel",
+			edges: ResizeEdgeBottom,
+			delta: Point{Y: -500},
+			exp:   Geometry{Origin: Point{X: 100, Y: 100}, Size: Size{W: 200, H: 1}},
+		},
+		{
+			name:   "right aspect",
+			edges:  ResizeEdgeRight,
+			delta:  Point{X: 100},
+			aspect: Size{W: 2, H: 1},
+			exp:    Geometry{Origin: Point{X: 100, Y: 100}, Size: Size{W: 300, H: 150}},
+		},
+		{
+			name:   "top left aspect",
+			edges:  ResizeEdgeTopLeft,
+			delta:  Point{X: -100},
+			aspect: Size{W: 2, H: 1},
+			exp:    Geometry{Origin: Point{X: 0, Y: 50}, Size: Size{W: 300, H: 150}},
+		},
+		{
+			name:  "bottom left",
+			edges: ResizeEdgeBottomLeft,
+			delta: Point{X: 20, Y: 30},
+			exp:   Geometry{Origin: Point{X: 120, Y: 100}, Size: Size{W: 180, H: 130}},
+		},
+		{
+			name:  "top right",
+			edges: ResizeEdgeTopRight,
+			delta: Point{X: 30, Y: -20},
+			exp:   Geometry{Origin: Point{X: 100, Y: 80}, Size: Size{W: 230, H: 120}},
+		},
+		{
+			name:  "bottom ignores horizontal motion",
+			edges: ResizeEdgeBottom,
+			delta: Point{X: 10, Y: 40},
+			exp:   Geometry{Origin: Point{X: 100, Y: 100}, Size: Size{W: 200, H: 140}},
+		},
+		{
+			name:  "top ignores horizontal motion",
+			edges: ResizeEdgeTop,
+			delta: Point{X: 40, Y: -10},
+			exp:   Geometry{Origin: Point{X: 100, Y: 90}, Size: Size{W: 200, H: 110}},
+		},
+		{
+			name:  "bottom left min size",
+			edges: ResizeEdgeBottomLeft,
+			delta: Point{X: 300, Y: -300},
+			min:   Size{W: 80, H: 40},
+			exp:   Geometry{Origin: Point{X: 220, Y: 100}, Size: Size{W: 80, H: 40}},
+		},
+		{
+			name:  "top right min size",
+			edges: ResizeEdgeTopRight,
+			delta: Point{X: -300, Y: 300},
+			min:   Size{W: 80, H: 40},
+			exp:   Geometry{Origin: Point{X: 100, Y: 160}, Size: Size{W: 80, H: 40}},
+		},
+		{
+			name:  "top min size",
+			edges: ResizeEdgeTop,
+			delta: Point{Y: 300},
+			min:   Size{W: 80, H: 40},
+			exp:   Geometry{Origin: Point{X: 100, Y: 160}, Size: Size{W: 200, H: 40}},
+		},
+		{
+			name:  "bottom left max size",
+			edges: ResizeEdgeBottomLeft,
+			delta: Point{X: -1000, Y: 1000},
+			max:   Size{W: 300, H: 150},
+			exp:   Geometry{Origin: Point{X: 0, Y: 100}, Size: Size{W: 300, H: 150}},
+		},
+		{
+			name:   "bottom aspect",
+			edges:  ResizeEdgeBottom,
+			delta:  Point{Y: 50},
+			aspect: Size{W: 2, H: 1},
+			exp:    Geometry{Origin: Point{X: 100, Y: 100}, Size: Size{W: 300, H: 150}},
+		},
+		{
+			name:   "top aspect",
+			edges:  ResizeEdgeTop,
+			delta:  Point{Y: -50},
+			aspect: Size{W: 2, H: 1},
+			exp:    Geometry{Origin: Point{X: 100, Y: 50}, Size: Size{W: 300, H: 150}},
+		},
+		{
+			name:   "bottom left aspect",
+			edges:  ResizeEdgeBottomLeft,
+			delta:  Point{X: -100, Y: 10},
+			aspect: Size{W: 2, H: 1},
+			exp:    Geometry{Origin: Point{X: 0, Y: 100}, Size: Size{W: 300, H: 150}},
+		},
+		{
+			name:   "top right aspect",
+			edges:  ResizeEdgeTopRight,
+			delta:  Point{Y: -100},
+			aspect: Size{W: 2, H: 1},
+			exp:    Geometry{Origin: Point{X: 100, Y: 0}, Size: Size{W: 400, H: 200}},
+		},
+		{
+			name:   "bottom aspect min size",
+			edges:  ResizeEdgeBottom,
+			delta:  Point{Y: -500},
+			min:    Size{W: 80, H: 60},
+			aspect: Size{W: 2, H: 1},
+			exp:    Geometry{Origin: Point{X: 100, Y: 100}, Size: Size{W: 120, H: 60}},
+		},
+		{
+			name:   "top right aspect min size",
+			edges:  ResizeEdgeTopRight,
+			delta:  Point{X: -500, Y: 500},
+			min:    Size{W: 80, H: 60},
+			aspect: Size{W: 2, H: 1},
+			exp:    Geometry{Origin: Point{X: 100, Y: 140}, Size: Size{W: 120, H: 60}},
+		},
+	} {
+		g := ResizeGeometry(geometry, tc.edges, tc.delta, tc.min, tc.max, tc.aspect)
+		if !GeometryEquals(g, tc.exp) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.exp, g)
+		}
+	}
+}
+
+func TestInferResizeEdges(t *testing.T) {
+	geometry := Geometry{Origin: Point{X: 100, Y: 100}, Size: Size{W: 200, H: 100}}
+
+	for _, tc := range []struct {
+		point Point
+		exp   uint32
+	}{
+		{Point{X: 110, Y: 110}, ResizeEdgeTopLeft},
+		{Point{X: 290, Y: 110}, ResizeEdgeTopRight},
+		{Point{X: 110, Y: 190}, ResizeEdgeBottomLeft},
+		{Point{X: 290, Y: 190}, ResizeEdgeBottomRight},
+		{Point{X: 400, Y: 0}, ResizeEdgeTopRight},
+		{Point{X: 0, Y: 400}, ResizeEdgeBottomLeft},
+		{Point{X: 200, Y: 110}, ResizeEdgeTop},
+		{Point{X: 200, Y: 190}, ResizeEdgeBottom},
+		{Point{X: 110, Y: 150}, ResizeEdgeLeft},
+		{Point{X: 290, Y: 150}, ResizeEdgeRight},
+		{Point{X: 200, Y: 150}, ResizeEdgeBottomRight},
+	} {
+		if edges := InferResizeEdges(geometry, tc.point); edges != tc.exp {
+			t.Errorf("%v: expected edges %d, got %d", tc.point, tc.exp, edges)
+		}
+	}
+}
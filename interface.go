@@ -124,8 +124,8 @@ type internalInterface struct {
 		Terminate func()
 	}
 	Input struct {
-		Created   func(*C.struct_libinput_device) bool
-		Destroyed func(*C.struct_libinput_device)
+		Created   func(InputDevice) bool
+		Destroyed func(InputDevice)
 	}
 }
 
@@ -313,14 +313,14 @@ func SetCompositorTerminateCb(cb func()) {
 
 // SetInputCreatedCb sets callback to trigger when input device is created.
 // Return value of callback does nothing. (Experimental).
-func SetInputCreatedCb(cb func(*C.struct_libinput_device) bool) {
+func SetInputCreatedCb(cb func(InputDevice) bool) {
 	wlcInterface.Input.Created = cb
 	C.set_input_created_cb()
 }
 
 // SetInputDestroyedCb sets callback to trigger when input device was
 // destroyed. (Experimental).
-func SetInputDestroyedCb(cb func(*C.struct_libinput_device)) {
+func SetInputDestroyedCb(cb func(InputDevice)) {
 	wlcInterface.Input.Destroyed = cb
 	C.set_input_destroyed_cb()
 }
@@ -454,10 +454,8 @@ func _goHandlePointerButton(view C.wlc_handle, time C.uint32_t, modifiers *C.str
 //export _goHandlePointerScroll
 func _goHandlePointerScroll(view C.wlc_handle, time C.uint32_t, modifiers *C.struct_wlc_modifiers, axisBits C.uint8_t, amount *C.double) C._Bool {
 	// convert double[2] to [2]float64
-	goAmount := [2]float64{
-		*(*float64)(amount),
-		*(*float64)(unsafe.Pointer(uintptr(unsafe.Pointer(amount)) + unsafe.Sizeof(*amount))),
-	}
+	var goAmount [2]float64
+	copy(goAmount[:], unsafe.Slice((*float64)(unsafe.Pointer(amount)), 2))
 	return C._Bool(wlcInterface.Pointer.Scroll(
 		View(view),
 		uint32(time),
@@ -506,10 +504,10 @@ func _goHandleCompositorTerminate() {
 
 //export _goHandleInputCreated
 func _goHandleInputCreated(device *C.struct_libinput_device) C._Bool {
-	return C._Bool(wlcInterface.Input.Created(device))
+	return C._Bool(wlcInterface.Input.Created(InputDevice{device: device}))
 }
 
 //export _goHandleInputDestroyed
 func _goHandleInputDestroyed(device *C.struct_libinput_device) {
-	wlcInterface.Input.Destroyed(device)
+	wlcInterface.Input.Destroyed(InputDevice{device: device})
 }
@@ -0,0 +1,144 @@
+package wlc
+
+import "strings"
+
+// xkb names of the modifiers and leds reported by wlc, in bit order.
+var (
+	xkbModNames = []string{"Shift", "Lock", "Control", "Mod1", "Mod2", "Mod3", "Mod4", "Mod5"}
+	xkbLedNames = []string{"Num Lock", "Caps Lock", "Scroll Lock"}
+)
+
+// String gets the names of the modifiers set in m, e.g. "Ctrl+Shift".
+func (m ModifierBit) String() string {
+	var names []string
+	for _, mod := range modifierOrder {
+		if uint32(m)&mod.bit != 0 {
+			names = append(names, mod.name)
+		}
+	}
+	return strings.Join(names, "+")
+}
+
+// String gets the names of the leds set in l, e.g. "Num+Caps".
+func (l LedBit) String() string {
+	var names []string
+	for i, name := range []string{"Num", "Caps", "Scroll"} {
+		if l&(1<<uint(i)) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, "+")
+}
+
+// Modifier gets the modifiers set in m.
+func (m *Modifiers) Modifier() ModifierBit {
+	return ModifierBit(m.Mods)
+}
+
+// Led gets the leds set in m.
+func (m *Modifiers) Led() LedBit {
+	return LedBit(m.Leds)
+}
+
+// Has checks if all modifiers in mods are set.
+func (m *Modifiers) Has(mods ModifierBit) bool {
+	return m.Mods&uint32(mods) == uint32(mods)
+}
+
+// NumLock checks if the Num Lock led is on.
+func (m *Modifiers) NumLock() bool {
+	return m.Leds&uint32(BitLedNum) != 0
+}
+
+// CapsLock checks if the Caps Lock led is on.
+func (m *Modifiers) CapsLock() bool {
+	return m.Leds&BitLedCaps != 0
+}
+
+// ScrollLock checks if the Scroll Lock led is on.
+func (m *Modifiers) ScrollLock() bool {
+	return m.Leds&BitLedScroll != 0
+}
+
+// KeyboardGetModifiers gets the current state of the keyboard modifiers and
+// leds.
+func KeyboardGetModifiers() Modifiers {
+	var mods Modifiers
+
+	state := KeyboardGetXKBState()
+	if state == nil {
+		return mods
+	}
+
+	for i, name := range xkbModNames {
+		if state.ModActive(name) {
+			mods.Mods |= 1 << uint(i)
+		}
+	}
+
+	for i, name := range xkbLedNames {
+		if state.LedActive(name) {
+			mods.Leds |= 1 << uint(i)
+		}
+	}
+
+	return mods
+}
+
+// HeldKey is a key currently held.
+type HeldKey struct {
+	Key uint32
+	// Keysym is the keysym of the key without modifiers applied.
+	Keysym uint32
+	// Utf32 is the Unicode/UTF-32 codepoint of the key with the current
+	// modifiers applied, or 0.
+	Utf32 uint32
+}
+
+// KeyboardGetHeldKeys gets currently held keys with their keysyms and
+// codepoints.
+func KeyboardGetHeldKeys() []HeldKey {
+	keys := KeyboardGetCurrentKeys()
+	if len(keys) == 0 {
+		return nil
+	}
+
+	mods := KeyboardGetModifiers()
+	held := make([]HeldKey, len(keys))
+	for i, key := range keys {
+		held[i] = HeldKey{
+			Key:    key,
+			Keysym: KeyboardGetKeysymForKey(key, nil),
+			Utf32:  KeyboardGetUtf32ForKey(key, &mods),
+		}
+	}
+
+	return held
+}
+
+// KeyboardKeyHeld checks if key is currently held.
+func KeyboardKeyHeld(key uint32) bool {
+	for _, k := range KeyboardGetCurrentKeys() {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyboardKeysymHeld checks if a key producing keysym is currently held,
+// with or without the current modifiers applied.
+func KeyboardKeysymHeld(keysym uint32) bool {
+	keys := KeyboardGetCurrentKeys()
+	if len(keys) == 0 {
+		return false
+	}
+
+	mods := KeyboardGetModifiers()
+	for _, key := range keys {
+		if KeyboardGetKeysymForKey(key, nil) == keysym || KeyboardGetKeysymForKey(key, &mods) == keysym {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package wlc
+
+import "testing"
+
+func TestModifiers(t *testing.T) {
+	m := Modifiers{Mods: BitModCtrl | uint32(BitModShift), Leds: BitLedCaps}
+
+	if s := m.Modifier().String(); s != "Ctrl+Shift" {
+		t.Errorf("expected modifiers 'Ctrl+Shift', got '%s'", s)
+	}
+
+	if s := m.Led().String(); s != "Caps" {
+		t.Errorf("expected leds 'Caps', got '%s'", s)
+	}
+
+	if !m.CapsLock() || m.NumLock() || m.ScrollLock() {
+		t.Error("expected only Caps Lock to be on")
+	}
+
+	if !m.Has(BitModCtrl) || !m.Has(BitModCtrl|BitModShift) {
+		t.Error("expected Ctrl and Shift to be set")
+	}
+
+	if m.Has(BitModCtrl | BitModAlt) {
+		t.Error("expected Ctrl+Alt not to be set")
+	}
+}
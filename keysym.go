@@ -0,0 +1,41 @@
+package wlc
+
+/*
+#cgo LDFLAGS: -lxkbcommon
+#include <stdlib.h>
+#include <xkbcommon/xkbcommon.h>
+*/
+import "C"
+
+import "unsafe"
+
+// KeysymFromName gets the keysym named name, e.g. "Return" or "a". If the
+// name doesn't match exactly it's matched case insensitively. Returns 0 if
+// there is no such keysym.
+func KeysymFromName(name string) uint32 {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	if sym := C.xkb_keysym_from_name(cname, C.XKB_KEYSYM_NO_FLAGS); sym != 0 {
+		return uint32(sym)
+	}
+
+	return uint32(C.xkb_keysym_from_name(cname, C.XKB_KEYSYM_CASE_INSENSITIVE))
+}
+
+// KeysymName gets the name of keysym. Keysyms without a name are named by
+// their hexadecimal value.
+func KeysymName(keysym uint32) string {
+	var buf [64]C.char
+	n := C.xkb_keysym_get_name(C.xkb_keysym_t(keysym), &buf[0], C.size_t(len(buf)))
+	if n < 0 {
+		return ""
+	}
+	return C.GoString(&buf[0])
+}
+
+// KeysymToUtf32 gets the Unicode/UTF-32 codepoint of keysym. Returns 0 if the
+// keysym has no Unicode representation.
+func KeysymToUtf32(keysym uint32) uint32 {
+	return uint32(C.xkb_keysym_to_utf32(C.xkb_keysym_t(keysym)))
+}
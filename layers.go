@@ -0,0 +1,142 @@
+package wlc
+
+import "sort"
+
+// Layer is a stacking layer. Views in a higher layer are always stacked above
+// views in a lower layer.
+type Layer int
+
+const (
+	// LayerBackground is for wallpapers and desktop views.
+	LayerBackground Layer = iota
+	// LayerBottom is for views below normal views, e.g. desktop widgets.
+	LayerBottom
+	// LayerNormal is the default layer.
+	LayerNormal
+	// LayerTop is for panels and docks.
+	LayerTop
+	// LayerOverlay is for on-screen displays, notifications and lock
+	// screens.
+	LayerOverlay
+)
+
+var layerNames = map[Layer]string{
+	LayerBackground: "background",
+	LayerBottom:     "bottom",
+	LayerNormal:     "normal",
+	LayerTop:        "top",
+	LayerOverlay:    "overlay",
+}
+
+func (l Layer) String() string {
+	if name, ok := layerNames[l]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// Layers assigns stacking layers to views and keeps the view stack of each
+// output ordered by layer. Views without a layer inherit the layer of their
+// parent, or LayerNormal if they have none. Within a layer the relative
+// stacking order is kept.
+//
+// Call ViewCreated, ViewDestroyed and ViewMoveToOutput from the respective
+// callbacks, and use Raise and Lower instead of View.BringToFront and
+// View.SendToBack. StateManager, Scratchpad, ViewTree and Interactive bring
+// views to front themselves, set their RaiseView hook to Raise to keep the
+// layer order.
+type Layers struct {
+	layers map[View]Layer
+}
+
+// NewLayers initializes layers.
+func NewLayers() *Layers {
+	return &Layers{
+		layers: make(map[View]Layer),
+	}
+}
+
+// Layer gets the layer of view.
+func (l *Layers) Layer(view View) Layer {
+	seen := make(map[View]bool)
+	for view != 0 && !seen[view] {
+		if layer, ok := l.layers[view]; ok {
+			return layer
+		}
+		seen[view] = true
+		view = view.GetParent()
+	}
+	return LayerNormal
+}
+
+// SetLayer sets the layer of view and restacks its output.
+func (l *Layers) SetLayer(view View, layer Layer) {
+	l.layers[view] = layer
+	l.Restack(view.GetOutput())
+}
+
+// Raise brings view to the front of its layer.
+func (l *Layers) Raise(view View) {
+	view.BringToFront()
+	l.Restack(view.GetOutput())
+}
+
+// Lower sends view to the back of its layer.
+func (l *Layers) Lower(view View) {
+	view.SendToBack()
+	l.Restack(view.GetOutput())
+}
+
+// Restack orders the views of output by layer. Views are moved within the
+// stack, the order of the output views (used for tiling) is not changed.
+func (l *Layers) Restack(output Output) {
+	// views are in stack order, the bottom most view first.
+	views := output.GetViews()
+	sorted := make([]View, len(views))
+	copy(sorted, views)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return l.Layer(sorted[i]) < l.Layer(sorted[j])
+	})
+
+	for i := range views {
+		if views[i] != sorted[i] {
+			// stacking each view above the one sorted below it keeps
+			// the relative order of all views handled so far.
+			for j := i; j < len(sorted); j++ {
+				if j == 0 {
+					sorted[j].SendToBack()
+				} else {
+					sorted[j].BringAbove(sorted[j-1])
+				}
+			}
+			return
+		}
+	}
+}
+
+// ViewCreated restacks the output of view. Always returns true so it can be
+// used directly as view created callback.
+func (l *Layers) ViewCreated(view View) bool {
+	l.Restack(view.GetOutput())
+	return true
+}
+
+// ViewDestroyed forgets the layer of view.
+func (l *Layers) ViewDestroyed(view View) {
+	delete(l.layers, view)
+}
+
+// ViewMoveToOutput restacks the output view was moved to.
+func (l *Layers) ViewMoveToOutput(view View, from, to Output) {
+	l.Restack(to)
+}
+
+// raiseView brings view to front with raise, or with View.BringToFront if
+// raise is nil.
+func raiseView(raise func(View), view View) {
+	if raise != nil {
+		raise(view)
+		return
+	}
+	view.BringToFront()
+}
@@ -0,0 +1,152 @@
+package wlc
+
+// KeyboardLayouts remembers the keyboard layout of every view, switching the
+// xkb layout when focus changes. Views without a remembered layout get
+// Default. Layout changes made by xkb itself, e.g. with a group toggle
+// option, are remembered as well. The focused view is sent the new layout
+// right away, see View.SendKeyboardModifiers.
+//
+// Call ViewFocus, ViewDestroyed and KeyboardKey from the respective
+// callbacks. Cycle can be bound directly with KeyBindings.
+type KeyboardLayouts struct {
+	// Default is the layout of views without a remembered layout.
+	Default uint32
+	// OnChange is called when the active layout changes, if not nil.
+	OnChange func(view View, layout uint32, name string)
+
+	layouts map[View]uint32
+	focused View
+	// active layout last seen.
+	active uint32
+}
+
+// NewKeyboardLayouts initializes keyboard layouts.
+func NewKeyboardLayouts() *KeyboardLayouts {
+	return &KeyboardLayouts{
+		layouts: make(map[View]uint32),
+	}
+}
+
+// Layout gets the index of the active layout.
+func (l *KeyboardLayouts) Layout() uint32 {
+	if state := KeyboardGetXKBState(); state != nil {
+		return state.Layout()
+	}
+	return 0
+}
+
+// ViewLayout gets the remembered layout of view. Returns false if view has no
+// remembered layout.
+func (l *KeyboardLayouts) ViewLayout(view View) (uint32, bool) {
+	if view == l.focused && view != 0 {
+		return l.Layout(), true
+	}
+	layout, ok := l.layouts[view]
+	return layout, ok
+}
+
+// SetLayout activates layout idx for the focused view. Indexes out of range
+// wrap around.
+func (l *KeyboardLayouts) SetLayout(idx uint32) {
+	state := KeyboardGetXKBState()
+	if state == nil {
+		return
+	}
+
+	if n := state.Keymap().NumLayouts(); n > 0 {
+		idx %= n
+	}
+
+	if state.Layout() != idx {
+		state.SetLayout(idx)
+		if l.focused != 0 {
+			l.focused.SendKeyboardModifiers(state)
+		}
+	}
+	l.update(state)
+}
+
+// Next activates the next layout, after the last comes the first.
+func (l *KeyboardLayouts) Next() {
+	l.SetLayout(l.Layout() + 1)
+}
+
+// Prev activates the previous layout, before the first comes the last.
+func (l *KeyboardLayouts) Prev() {
+	state := KeyboardGetXKBState()
+	if state == nil {
+		return
+	}
+
+	n := state.Keymap().NumLayouts()
+	if n == 0 {
+		return
+	}
+	l.SetLayout(state.Layout() + n - 1)
+}
+
+// Cycle activates the next layout. It has the signature of a KeyBinding
+// action.
+func (l *KeyboardLayouts) Cycle(view View) {
+	l.Next()
+}
+
+// ViewFocus remembers the layout of view when it loses focus, and activates
+// its remembered layout when it gains focus, sending it to the client.
+func (l *KeyboardLayouts) ViewFocus(view View, focus bool) {
+	if !focus {
+		if view == l.focused {
+			l.layouts[view] = l.Layout()
+			l.focused = 0
+		}
+		return
+	}
+
+	l.focused = view
+	layout, ok := l.layouts[view]
+	if !ok {
+		layout = l.Default
+	}
+	l.SetLayout(layout)
+
+	// wlc sends the modifiers of the last key event on focus, which don't
+	// include layout changes made since.
+	if state := KeyboardGetXKBState(); state != nil {
+		view.SendKeyboardModifiers(state)
+	}
+}
+
+// ViewDestroyed forgets view.
+func (l *KeyboardLayouts) ViewDestroyed(view View) {
+	delete(l.layouts, view)
+	if view == l.focused {
+		l.focused = 0
+	}
+}
+
+// KeyboardKey picks up layout changes made by xkb. Always returns false so
+// the event is passed on to clients.
+func (l *KeyboardLayouts) KeyboardKey(view View, time uint32, modifiers Modifiers, key uint32, state KeyState) bool {
+	if s := KeyboardGetXKBState(); s != nil {
+		l.update(s)
+	}
+	return false
+}
+
+// update remembers the active layout for the focused view and notifies if it
+// changed.
+func (l *KeyboardLayouts) update(state *XKBState) {
+	layout := state.Layout()
+	if l.focused != 0 {
+		l.layouts[l.focused] = layout
+	}
+
+	if layout == l.active {
+		return
+	}
+
+	l.active = layout
+	if l.OnChange != nil {
+		l.OnChange(l.focused, layout, state.Keymap().LayoutName(layout))
+	}
+}
@@ -0,0 +1,300 @@
+package wlc
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// keysyms used by the lock, normally found in xkbcommon-keysyms.h.
+const (
+	keysymBackSpace = 0xff08
+	keysymReturn    = 0xff0d
+	keysymEscape    = 0xff1b
+	keysymKPEnter   = 0xff8d
+)
+
+// Authenticator authenticates the user unlocking a session, e.g. using PAM.
+type Authenticator interface {
+	// Authenticate returns nil if password is correct.
+	Authenticate(password string) error
+}
+
+// AuthenticatorFunc is an Authenticator implemented by a function.
+type AuthenticatorFunc func(password string) error
+
+// Authenticate calls f(password).
+func (f AuthenticatorFunc) Authenticate(password string) error {
+	return f(password)
+}
+
+// Lock is a session lock which doesn't depend on any client. While locked
+// every view is hidden by clearing its visibility bitmask, including views
+// created while locked, all input is consumed and the lock UI is drawn from
+// the output post render hook. The password is typed directly into the lock
+// and the session is only unlocked when the Authenticator approves it.
+//
+// While locked, masks set by the managers of this package, such as
+// WorkspaceManager, Scratchpad and ViewRules, are kept by the lock and
+// applied when the session is unlocked. Compositors setting view masks
+// directly should check Locked first. wlc runs a single compositor per
+// process, so there is a single session and only one Lock can be locked at
+// a time; locking while another Lock is locked does nothing.
+//
+// Call ViewCreated, ViewDestroyed, OutputRenderPost and the input methods
+// (KeyboardKey, PointerButton, PointerScroll, PointerMotion and Touch) from
+// the respective callbacks, before any other handling of the event.
+type Lock struct {
+	Authenticator Authenticator
+	// Render draws the lock UI on output. typed is the number of
+	// characters typed and err is the error of the last failed attempt. If
+	// nil the output is filled with Color.
+	Render func(output Output, typed int, err error)
+	// Color is the RGBA color used by the default lock UI.
+	Color [4]byte
+	// OnUnlock is called after the session is unlocked, if not nil.
+	OnUnlock func()
+
+	locked   bool
+	masks    map[View]uint32
+	password []rune
+	err      error
+	// buffer of the default lock UI, filled with bufferColor.
+	buffer      []byte
+	bufferColor [4]byte
+}
+
+// NewLock initializes a lock using auth to authenticate.
+func NewLock(auth Authenticator) *Lock {
+	return &Lock{
+		Authenticator: auth,
+		Color:         [4]byte{0, 0, 0, 255},
+		masks:         make(map[View]uint32),
+	}
+}
+
+// Locked checks if the session is locked.
+func (l *Lock) Locked() bool {
+	return l.locked
+}
+
+// Lock locks the session, hiding all views and unfocusing them.
+func (l *Lock) Lock() {
+	if l.locked || sessionLock != nil {
+		return
+	}
+
+	l.locked = true
+	sessionLock = l
+	l.clearPassword()
+	l.err = nil
+	for _, output := range GetOutputs() {
+		for _, view := range output.GetViews() {
+			l.hide(view)
+		}
+		output.ScheduleRender()
+	}
+	ViewUnfocus()
+}
+
+// Unlock unlocks the session if password is approved by the Authenticator.
+func (l *Lock) Unlock(password string) error {
+	if !l.locked {
+		return nil
+	}
+
+	if l.Authenticator == nil {
+		return fmt.Errorf("no authenticator")
+	}
+
+	if err := l.Authenticator.Authenticate(password); err != nil {
+		return err
+	}
+
+	l.locked = false
+	if sessionLock == l {
+		sessionLock = nil
+	}
+	l.clearPassword()
+	l.err = nil
+	// masks holds the last mask set for every view while locked.
+	for view, mask := range l.masks {
+		view.SetMask(mask)
+		delete(l.masks, view)
+	}
+
+	for _, output := range GetOutputs() {
+		output.ScheduleRender()
+	}
+
+	if l.OnUnlock != nil {
+		l.OnUnlock()
+	}
+
+	return nil
+}
+
+// ViewCreated hides view if the session is locked. Always returns true so it
+// can be used directly as view created callback.
+func (l *Lock) ViewCreated(view View) bool {
+	if l.locked {
+		l.hide(view)
+	}
+	return true
+}
+
+// ViewDestroyed forgets view.
+func (l *Lock) ViewDestroyed(view View) {
+	delete(l.masks, view)
+}
+
+// OutputRenderPost draws the lock UI on output if the session is locked.
+func (l *Lock) OutputRenderPost(output Output) {
+	if !l.locked {
+		return
+	}
+
+	if l.Render != nil {
+		l.Render(output, len(l.password), l.err)
+		return
+	}
+
+	res := output.GetResolution()
+	if res == nil || res.W == 0 || res.H == 0 {
+		return
+	}
+
+	size := int(res.W) * int(res.H) * 4
+	if len(l.buffer) != size || l.bufferColor != l.Color {
+		l.buffer = make([]byte, size)
+		l.bufferColor = l.Color
+		for i := 0; i < size; i += 4 {
+			copy(l.buffer[i:i+4], l.Color[:])
+		}
+	}
+
+	PixelsWrite(RGBA8888, Geometry{Size: *res}, unsafe.Pointer(&l.buffer[0]))
+}
+
+// KeyboardKey handles password input while locked. Return or keypad Enter
+// tries to unlock, BackSpace removes the last character and Escape clears
+// the input. Returns true while locked, meaning the event is not sent to
+// clients.
+func (l *Lock) KeyboardKey(view View, time uint32, modifiers Modifiers, key uint32, state KeyState) bool {
+	if !l.locked {
+		return false
+	}
+
+	if state != KeyStatePressed {
+		return true
+	}
+
+	switch KeyboardGetKeysymForKey(key, nil) {
+	case keysymReturn, keysymKPEnter:
+		l.err = l.Unlock(string(l.password))
+		l.clearPassword()
+	case keysymBackSpace:
+		if n := len(l.password); n > 0 {
+			l.password[n-1] = 0
+			l.password = l.password[:n-1]
+		}
+	case keysymEscape:
+		l.clearPassword()
+	default:
+		if r := KeyboardGetUtf32ForKey(key, &modifiers); r != 0 {
+			l.typeRune(rune(r))
+		}
+	}
+
+	if l.locked {
+		for _, output := range GetOutputs() {
+			output.ScheduleRender()
+		}
+	}
+
+	return true
+}
+
+// PointerButton returns true while locked, meaning the event is not sent to
+// clients.
+func (l *Lock) PointerButton(view View, time uint32, modifiers Modifiers, button uint32, state ButtonState, pos *Point) bool {
+	return l.locked
+}
+
+// PointerScroll returns true while locked, meaning the event is not sent to
+// clients.
+func (l *Lock) PointerScroll(view View, time uint32, modifiers Modifiers, axis uint8, amount [2]float64) bool {
+	return l.locked
+}
+
+// PointerMotion returns true while locked, meaning the event is not sent to
+// clients. The pointer position must still be updated by the compositor.
+func (l *Lock) PointerMotion(view View, time uint32, pos *Point) bool {
+	return l.locked
+}
+
+// Touch returns true while locked, meaning the event is not sent to clients.
+func (l *Lock) Touch(view View, time uint32, modifiers Modifiers, touch TouchType, slot int32, pos *Point) bool {
+	return l.locked
+}
+
+func (l *Lock) hide(view View) {
+	if _, ok := l.masks[view]; !ok {
+		l.masks[view] = view.GetMask()
+	}
+	view.SetMask(0)
+}
+
+// typeRune appends r to the password. The password is moved to a larger
+// array by hand, so the old array can be cleared.
+func (l *Lock) typeRune(r rune) {
+	if len(l.password) == cap(l.password) {
+		password := make([]rune, len(l.password), 2*cap(l.password)+16)
+		copy(password, l.password)
+		l.clearPassword()
+		l.password = password
+	}
+	l.password = append(l.password, r)
+}
+
+// clearPassword clears the typed password, overwriting it in memory.
+func (l *Lock) clearPassword() {
+	for i := range l.password {
+		l.password[i] = 0
+	}
+	l.password = l.password[:0]
+}
+
+// keepMask keeps mask as the mask of view while locked. Returns false if l
+// is nil or not locked.
+func (l *Lock) keepMask(view View, mask uint32) bool {
+	if l == nil || !l.locked {
+		return false
+	}
+	l.masks[view] = mask
+	return true
+}
+
+// sessionLock is the locked Lock, if any. It's global since wlc runs a
+// single session per process, and the managers setting view masks need to
+// know about it without every one of them being handed the lock.
+var sessionLock *Lock
+
+// setViewMask sets the visibility bitmask of view. While the session is
+// locked the mask is kept by the lock and view stays hidden.
+func setViewMask(view View, mask uint32) {
+	if sessionLock.keepMask(view, mask) {
+		view.SetMask(0)
+		return
+	}
+	view.SetMask(mask)
+}
+
+// viewMask gets the visibility bitmask of view, as set by setViewMask.
+func viewMask(view View) uint32 {
+	if sessionLock != nil {
+		if mask, ok := sessionLock.masks[view]; ok {
+			return mask
+		}
+	}
+	return view.GetMask()
+}
@@ -0,0 +1,103 @@
+package wlc
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeAuthenticator approves a single password and counts attempts.
+type fakeAuthenticator struct {
+	password string
+	attempts int
+}
+
+func (a *fakeAuthenticator) Authenticate(password string) error {
+	a.attempts++
+	if password != a.password {
+		return errors.New("wrong password")
+	}
+	return nil
+}
+
+func TestLockUnlockRejected(t *testing.T) {
+	auth := &fakeAuthenticator{password: "secret"}
+	lock := NewLock(auth)
+
+	if err := lock.Unlock("wrong"); err != nil || auth.attempts != 0 {
+		t.Errorf("expected unlocking an unlocked session to be a no-op, got %v", err)
+	}
+
+	// locked without hiding views, which needs wlc.
+	lock.locked = true
+
+	if err := lock.Unlock("wrong"); err == nil {
+		t.Error("expected wrong password to fail")
+	}
+
+	if !lock.Locked() || auth.attempts != 1 {
+		t.Errorf("expected session to stay locked after 1 attempt, got %d attempts", auth.attempts)
+	}
+
+	lock.Authenticator = nil
+	if err := lock.Unlock("secret"); err == nil || !lock.Locked() {
+		t.Error("expected unlocking without authenticator to fail")
+	}
+}
+
+func TestLockPassword(t *testing.T) {
+	lock := NewLock(nil)
+	for _, r := range "secret" {
+		lock.typeRune(r)
+	}
+
+	typed := lock.password[:cap(lock.password)]
+	lock.clearPassword()
+
+	if len(lock.password) != 0 {
+		t.Errorf("expected password to be cleared, got '%s'", string(lock.password))
+	}
+
+	for i, r := range typed {
+		if r != 0 {
+			t.Errorf("expected password to be overwritten, got %q at %d", r, i)
+		}
+	}
+}
+
+func TestLockKeepMask(t *testing.T) {
+	lock := NewLock(&fakeAuthenticator{password: "secret"})
+
+	if lock.keepMask(3, 2) {
+		t.Error("expected mask not to be kept while unlocked")
+	}
+
+	var none *Lock
+	if none.keepMask(3, 2) {
+		t.Error("expected mask not to be kept without lock")
+	}
+
+	lock.locked = true
+	sessionLock = lock
+	defer func() { sessionLock = nil }()
+
+	// the owner of the view sets its mask twice while locked, the last
+	// mask is applied on unlock.
+	for _, mask := range []uint32{2, 4} {
+		if !lock.keepMask(3, mask) {
+			t.Errorf("expected mask %d to be kept while locked", mask)
+		}
+	}
+
+	if mask := lock.masks[3]; mask != 4 {
+		t.Errorf("expected kept mask 4, got %d", mask)
+	}
+
+	if mask := viewMask(3); mask != 4 {
+		t.Errorf("expected pending mask 4, got %d", mask)
+	}
+
+	lock.ViewDestroyed(3)
+	if _, ok := lock.masks[3]; ok {
+		t.Error("expected mask of destroyed view to be forgotten")
+	}
+}
@@ -2,13 +2,15 @@ package wlc
 
 /*
 #cgo LDFLAGS: -lwlc
-#include <stdlib.h>
 #include <wlc/wlc.h>
+
+// resolution is passed by value to keep it off the Go heap.
+static inline void output_set_resolution(wlc_handle output, struct wlc_size resolution, uint32_t scale) {
+	wlc_output_set_resolution(output, &resolution, scale);
+}
 */
 import "C"
 
-import "unsafe"
-
 // Output is a wlc_handle describing an output object in wlc.
 type Output C.wlc_handle
 
@@ -58,9 +60,7 @@ func (o Output) GetVirtualResolution() *Size {
 
 // SetResolution sets output resolution.
 func (o Output) SetResolution(resolution Size, scale uint32) {
-	csize := resolution.c()
-	defer C.free(unsafe.Pointer(csize))
-	C.wlc_output_set_resolution(C.wlc_handle(o), csize, C.uint32_t(scale))
+	C.output_set_resolution(C.wlc_handle(o), resolution.c(), C.uint32_t(scale))
 }
 
 // GetScale returns scale factor.
@@ -98,7 +98,7 @@ func (o Output) GetMutableViews() []View {
 // SetViews sets views in stack order. This will also change mutable
 // views. Returns false on failure.
 func (o Output) SetViews(views []View) bool {
-	cviews, len := viewHandlesSliceToCArray(views)
+	cviews, len := viewHandlesSliceToC(views)
 	return bool(C.wlc_output_set_views(C.wlc_handle(o), cviews, len))
 }
 
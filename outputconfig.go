@@ -0,0 +1,184 @@
+package wlc
+
+// OutputConfig describes the configuration of outputs matching Name.
+type OutputConfig struct {
+	// Name is a glob pattern matched against Output.Name, see MatchGlob.
+	Name string
+	// Enabled disables the output if false. wlc doesn't use disabled
+	// outputs. nil means enabled.
+	Enabled *bool
+	// Resolution sets the output resolution, if not nil.
+	Resolution *Size
+	// Scale is the scale used with Resolution. 0 means 1.
+	Scale uint32
+	// Position is the position of the output in the output layout. wlc
+	// has no notion of a layout, it's used by the compositor e.g. to move
+	// the pointer between outputs.
+	Position *Point
+	// Sleep puts the output to sleep.
+	Sleep bool
+	// Workspace is the workspace shown on the output. Only used with a
+	// WorkspaceManager, see OutputConfigs.Workspaces.
+	Workspace string
+	// Mask sets the visibility bitmask of the output, if not 0 and no
+	// WorkspaceManager is used.
+	Mask uint32
+}
+
+// OutputConfigs applies output configurations when outputs are created. The
+// first configuration with a name matching the output is used. Outputs
+// without a matching configuration get Default, placed to the right of the
+// other outputs unless Default has a position.
+//
+// Call OutputCreated and OutputDestroyed from the respective callbacks.
+type OutputConfigs struct {
+	Default OutputConfig
+	// Workspaces is used to show the configured workspace on outputs, if
+	// not nil. Outputs without a configured workspace are passed to
+	// WorkspaceManager.OutputCreated, so it should not be called by the
+	// compositor as well.
+	Workspaces *WorkspaceManager
+
+	configs []OutputConfig
+	// names are the compiled Name patterns of configs.
+	names   []Matcher
+	applied map[Output]OutputConfig
+}
+
+// NewOutputConfigs initializes output configurations.
+func NewOutputConfigs(configs ...OutputConfig) *OutputConfigs {
+	c := &OutputConfigs{
+		applied: make(map[Output]OutputConfig),
+	}
+	for _, config := range configs {
+		c.Add(config)
+	}
+	return c
+}
+
+// Add appends config to the list of configurations. It's only applied to
+// outputs created after it was added, or by calling Apply.
+func (c *OutputConfigs) Add(config OutputConfig) {
+	c.configs = append(c.configs, config)
+	c.names = append(c.names, MatchGlob(config.Name))
+}
+
+// Configs gets the configurations in the order they were added.
+func (c *OutputConfigs) Configs() []OutputConfig {
+	configs := make([]OutputConfig, len(c.configs))
+	copy(configs, c.configs)
+	return configs
+}
+
+// Match gets the configuration for an output named name. Returns false if no
+// configuration matches.
+func (c *OutputConfigs) Match(name string) (OutputConfig, bool) {
+	for i, config := range c.configs {
+		if c.names[i].MatchString(name) {
+			return config, true
+		}
+	}
+	return OutputConfig{}, false
+}
+
+// OutputCreated applies the configuration of output. Returns false if the
+// output is disabled, so it can be used directly as output created callback.
+func (c *OutputConfigs) OutputCreated(output Output) bool {
+	if !c.config(output).enabled() {
+		return false
+	}
+	c.Apply(output)
+	return true
+}
+
+// OutputDestroyed forgets the configuration of output.
+func (c *OutputConfigs) OutputDestroyed(output Output) {
+	delete(c.applied, output)
+}
+
+// Apply applies the matching configuration to output.
+func (c *OutputConfigs) Apply(output Output) {
+	config := c.config(output)
+
+	if config.Resolution != nil {
+		scale := config.Scale
+		if scale == 0 {
+			scale = 1
+		}
+		output.SetResolution(*config.Resolution, scale)
+	}
+
+	output.SetSleep(config.Sleep)
+
+	if c.Workspaces != nil {
+		if config.Workspace == "" || c.Workspaces.Switch(output, config.Workspace) != nil {
+			c.Workspaces.OutputCreated(output)
+		}
+	} else if config.Mask != 0 {
+		output.SetMask(config.Mask)
+	}
+
+	// store the effective configuration.
+	config.Name = output.Name()
+	if res := output.GetResolution(); res != nil {
+		config.Resolution = res
+	}
+	config.Scale = output.GetScale()
+	config.Mask = output.GetMask()
+	if c.Workspaces != nil {
+		if ws := c.Workspaces.Current(output); ws != nil {
+			config.Workspace = ws.Name
+		}
+	}
+	if config.Position == nil {
+		pos := c.nextPosition(output)
+		config.Position = &pos
+	}
+	c.applied[output] = config
+}
+
+// Config gets the effective configuration of output. Returns false if no
+// configuration was applied to output.
+func (c *OutputConfigs) Config(output Output) (OutputConfig, bool) {
+	config, ok := c.applied[output]
+	return config, ok
+}
+
+// Position gets the position of output in the output layout.
+func (c *OutputConfigs) Position(output Output) Point {
+	if config, ok := c.applied[output]; ok && config.Position != nil {
+		return *config.Position
+	}
+	return PointZero
+}
+
+// config gets the matching configuration of output, or Default.
+func (c *OutputConfigs) config(output Output) OutputConfig {
+	if config, ok := c.Match(output.Name()); ok {
+		return config
+	}
+	return c.Default
+}
+
+func (config OutputConfig) enabled() bool {
+	return config.Enabled == nil || *config.Enabled
+}
+
+// nextPosition gets the position right of the right most output.
+func (c *OutputConfigs) nextPosition(output Output) Point {
+	var x int64
+	for o, config := range c.applied {
+		if o == output || config.Position == nil {
+			continue
+		}
+
+		right := int64(config.Position.X)
+		if res := o.GetVirtualResolution(); res != nil {
+			right += int64(res.W)
+		}
+		if right > x {
+			x = right
+		}
+	}
+	return Point{X: int32(x)}
+}
@@ -0,0 +1,41 @@
+package wlc
+
+import "testing"
+
+func TestOutputConfigsMatch(t *testing.T) {
+	disabled := false
+	configs := NewOutputConfigs(
+		OutputConfig{Name: "HDMI-*", Sleep: true},
+		OutputConfig{Name: "eDP-1", Enabled: &disabled},
+		OutputConfig{Name: "*", Scale: 2},
+	)
+
+	for _, tc := range []struct {
+		name    string
+		exp     string
+		enabled bool
+	}{
+		{"HDMI-A-1", "HDMI-*", true},
+		{"eDP-1", "eDP-1", false},
+		{"DP-2", "*", true},
+	} {
+		config, ok := configs.Match(tc.name)
+		if !ok {
+			t.Errorf("%s: expected a match", tc.name)
+			continue
+		}
+
+		if config.Name != tc.exp {
+			t.Errorf("%s: expected config '%s', got '%s'", tc.name, tc.exp, config.Name)
+		}
+
+		if config.enabled() != tc.enabled {
+			t.Errorf("%s: expected enabled %t", tc.name, tc.enabled)
+		}
+	}
+
+	configs = NewOutputConfigs(OutputConfig{Name: "HDMI-*"})
+	if _, ok := configs.Match("DP-1"); ok {
+		t.Error("expected no match for DP-1")
+	}
+}
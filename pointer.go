@@ -0,0 +1,267 @@
+package wlc
+
+// PointerController keeps the pointer on the outputs and moves it between
+// them. Outputs are placed in a layout, either by OutputConfigs positions or,
+// without it, left to right in the order of GetOutputs. When the pointer
+// leaves the focused output it's moved to the output at that position in the
+// layout, which is focused. Positions between or outside all outputs are
+// clamped to the nearest output.
+//
+// The pointer can be confined to a view or an output, e.g. for games or
+// during interactive moves.
+//
+// Call PointerMotion from the pointer motion callback, instead of calling
+// PointerSetPosition, and ViewDestroyed and OutputDestroyed from the
+// respective callbacks.
+type PointerController struct {
+	// Layout gets the output positions, if not nil.
+	Layout *OutputConfigs
+
+	confineView   View
+	confineOutput Output
+}
+
+// NewPointerController initializes a pointer controller using the output
+// positions of layout, which can be nil.
+func NewPointerController(layout *OutputConfigs) *PointerController {
+	return &PointerController{
+		Layout: layout,
+	}
+}
+
+// ConfineToView confines the pointer to the geometry of view.
+func (p *PointerController) ConfineToView(view View) {
+	p.confineView = view
+	p.confineOutput = 0
+}
+
+// ConfineToOutput confines the pointer to output.
+func (p *PointerController) ConfineToOutput(output Output) {
+	p.confineOutput = output
+	p.confineView = 0
+}
+
+// Release releases the confinement of the pointer.
+func (p *PointerController) Release() {
+	p.confineView = 0
+	p.confineOutput = 0
+}
+
+// Confined gets the view or output the pointer is confined to. Both are 0 if
+// the pointer isn't confined.
+func (p *PointerController) Confined() (View, Output) {
+	return p.confineView, p.confineOutput
+}
+
+// OutputGeometry gets the geometry of output in the layout.
+func (p *PointerController) OutputGeometry(output Output) Geometry {
+	for _, o := range p.layout() {
+		if o.output == output {
+			return o.geometry
+		}
+	}
+	return Geometry{}
+}
+
+// OutputAt gets the output at pos in the layout. Returns 0 if there is no
+// output at pos.
+func (p *PointerController) OutputAt(pos Point) Output {
+	for _, o := range p.layout() {
+		if geometryContainsPoint(o.geometry, pos) {
+			return o.output
+		}
+	}
+	return 0
+}
+
+// Warp moves the pointer to pos on output, focusing output.
+func (p *PointerController) Warp(output Output, pos Point) {
+	if output != GetFocusedOutput() {
+		output.Focus()
+	}
+	PointerSetPosition(pos)
+}
+
+// PointerMotion clamps pos to the outputs, or the confinement, moves the
+// pointer to the output at pos and sets the pointer position. pos is updated
+// to the new position, relative to the now focused output. Always returns
+// false so the event is passed on to clients.
+func (p *PointerController) PointerMotion(view View, time uint32, pos *Point) bool {
+	focused := GetFocusedOutput()
+	if focused == 0 {
+		PointerSetPosition(*pos)
+		return false
+	}
+
+	if p.confineView != 0 || p.confineOutput != 0 {
+		*pos = p.confine(focused, *pos)
+		PointerSetPosition(*pos)
+		return false
+	}
+
+	output, local := moveInLayout(p.layout(), focused, *pos)
+	if output != focused {
+		output.Focus()
+	}
+
+	*pos = local
+	PointerSetPosition(*pos)
+	return false
+}
+
+// ViewDestroyed releases the confinement to view.
+func (p *PointerController) ViewDestroyed(view View) {
+	if view == p.confineView {
+		p.confineView = 0
+	}
+}
+
+// OutputDestroyed releases the confinement to output.
+func (p *PointerController) OutputDestroyed(output Output) {
+	if output == p.confineOutput {
+		p.confineOutput = 0
+	}
+}
+
+// confine clamps pos on focused to the confinement.
+func (p *PointerController) confine(focused Output, pos Point) Point {
+	var area Geometry
+	if p.confineView != 0 {
+		g := p.confineView.GetGeometry()
+		if g == nil {
+			return pos
+		}
+		area = *g
+
+		// the view may be on another output than the pointer.
+		if output := p.confineView.GetOutput(); output != focused && output != 0 {
+			output.Focus()
+		}
+	} else {
+		if p.confineOutput != focused {
+			p.confineOutput.Focus()
+		}
+		area = Geometry{Size: outputSize(p.confineOutput)}
+	}
+
+	return clampPoint(pos, area)
+}
+
+// outputGeometry is the geometry of an output in the layout.
+type outputGeometry struct {
+	output   Output
+	geometry Geometry
+}
+
+// layout gets the geometries of all outputs.
+func (p *PointerController) layout() []outputGeometry {
+	outputs := GetOutputs()
+	layout := make([]outputGeometry, 0, len(outputs))
+
+	var x int32
+	for _, output := range outputs {
+		g := Geometry{Origin: Point{X: x}, Size: outputSize(output)}
+		if p.Layout != nil {
+			g.Origin = p.Layout.Position(output)
+		}
+		x += int32(g.Size.W)
+		layout = append(layout, outputGeometry{output: output, geometry: g})
+	}
+	return layout
+}
+
+// moveInLayout gets the output at pos, relative to focused, and the position
+// relative to that output. Positions outside all outputs are clamped to the
+// nearest output. Returns focused and pos if focused isn't in layout.
+func moveInLayout(layout []outputGeometry, focused Output, pos Point) (Output, Point) {
+	var origin Point
+	found := false
+	for _, o := range layout {
+		if o.output == focused {
+			origin = o.geometry.Origin
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return focused, pos
+	}
+
+	global := Point{X: origin.X + pos.X, Y: origin.Y + pos.Y}
+	for _, o := range layout {
+		if geometryContainsPoint(o.geometry, global) {
+			origin = o.geometry.Origin
+			return o.output, Point{X: global.X - origin.X, Y: global.Y - origin.Y}
+		}
+	}
+
+	output, nearest := nearestOutput(layout, global)
+	if output == 0 {
+		return focused, pos
+	}
+
+	origin = nearest.Origin
+	clamped := clampPoint(global, nearest)
+	return output, Point{X: clamped.X - origin.X, Y: clamped.Y - origin.Y}
+}
+
+// nearestOutput gets the output closest to pos and its geometry. Returns 0 if
+// layout has no output with a size.
+func nearestOutput(layout []outputGeometry, pos Point) (Output, Geometry) {
+	var nearest outputGeometry
+	best := int64(-1)
+	for _, o := range layout {
+		g := o.geometry
+		if g.Size.W == 0 || g.Size.H == 0 {
+			continue
+		}
+
+		clamped := clampPoint(pos, g)
+		dx, dy := int64(clamped.X-pos.X), int64(clamped.Y-pos.Y)
+		if d := dx*dx + dy*dy; best < 0 || d < best {
+			best = d
+			nearest = o
+		}
+	}
+	return nearest.output, nearest.geometry
+}
+
+func outputSize(output Output) Size {
+	if res := output.GetVirtualResolution(); res != nil {
+		return *res
+	}
+	return SizeZero
+}
+
+func geometryContainsPoint(g Geometry, pos Point) bool {
+	return pos.X >= g.Origin.X && pos.X < g.Origin.X+int32(g.Size.W) &&
+		pos.Y >= g.Origin.Y && pos.Y < g.Origin.Y+int32(g.Size.H)
+}
+
+// clampPoint clamps pos to the area of g.
+func clampPoint(pos Point, g Geometry) Point {
+	maxX := g.Origin.X + int32(g.Size.W) - 1
+	maxY := g.Origin.Y + int32(g.Size.H) - 1
+	if maxX < g.Origin.X {
+		maxX = g.Origin.X
+	}
+	if maxY < g.Origin.Y {
+		maxY = g.Origin.Y
+	}
+
+	return Point{
+		X: clampInt32(pos.X, g.Origin.X, maxX),
+		Y: clampInt32(pos.Y, g.Origin.Y, maxY),
+	}
+}
+
+func clampInt32(v, min, max int32) int32 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
@@ -0,0 +1,53 @@
+package wlc
+
+import "testing"
+
+func TestMoveInLayout(t *testing.T) {
+	// two outputs side by side, the second one lower.
+	layout := []outputGeometry{
+		{output: 1, geometry: Geometry{Size: Size{W: 100, H: 100}}},
+		{output: 2, geometry: Geometry{Origin: Point{X: 100, Y: 50}, Size: Size{W: 100, H: 100}}},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		focused Output
+		pos     Point
+		output  Output
+		exp     Point
+	}{
+		{"inside", 1, Point{X: 50, Y: 50}, 1, Point{X: 50, Y: 50}},
+		{"to the right", 1, Point{X: 120, Y: 60}, 2, Point{X: 20, Y: 10}},
+		{"to the left", 2, Point{X: -5, Y: 10}, 1, Point{X: 95, Y: 60}},
+		{"gap above second output", 1, Point{X: 150, Y: 10}, 2, Point{X: 50, Y: 0}},
+		{"beyond the right edge", 2, Point{X: 150, Y: -80}, 2, Point{X: 99, Y: 0}},
+		{"beyond the left edge", 1, Point{X: -30, Y: 120}, 1, Point{X: 0, Y: 99}},
+		{"unknown output", 3, Point{X: 500, Y: 500}, 3, Point{X: 500, Y: 500}},
+	} {
+		output, pos := moveInLayout(layout, tc.focused, tc.pos)
+		if output != tc.output || pos != tc.exp {
+			t.Errorf("%s: expected %v on output %d, got %v on output %d", tc.name, tc.exp, tc.output, pos, output)
+		}
+	}
+}
+
+func TestClampPoint(t *testing.T) {
+	g := Geometry{Origin: Point{X: 10, Y: 10}, Size: Size{W: 100, H: 50}}
+
+	for _, tc := range []struct {
+		pos Point
+		exp Point
+	}{
+		{Point{X: 50, Y: 20}, Point{X: 50, Y: 20}},
+		{Point{X: 0, Y: 0}, Point{X: 10, Y: 10}},
+		{Point{X: 200, Y: 200}, Point{X: 109, Y: 59}},
+	} {
+		if pos := clampPoint(tc.pos, g); pos != tc.exp {
+			t.Errorf("%v: expected %v, got %v", tc.pos, tc.exp, pos)
+		}
+
+		if !geometryContainsPoint(g, clampPoint(tc.pos, g)) {
+			t.Errorf("%v: expected clamped position inside %v", tc.pos, g)
+		}
+	}
+}
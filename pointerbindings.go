@@ -0,0 +1,352 @@
+package wlc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pointer buttons, as found in linux/input.h.
+const (
+	ButtonLeft    = 0x110
+	ButtonRight   = 0x111
+	ButtonMiddle  = 0x112
+	ButtonSide    = 0x113
+	ButtonExtra   = 0x114
+	ButtonForward = 0x115
+	ButtonBack    = 0x116
+	ButtonTask    = 0x117
+)
+
+// buttonNames maps button names used in binding specs to buttons.
+var buttonNames = map[string]uint32{
+	"left":    ButtonLeft,
+	"right":   ButtonRight,
+	"middle":  ButtonMiddle,
+	"side":    ButtonSide,
+	"extra":   ButtonExtra,
+	"forward": ButtonForward,
+	"back":    ButtonBack,
+	"task":    ButtonTask,
+}
+
+// ScrollDirection is the direction of a scroll binding.
+type ScrollDirection int
+
+const (
+	// ScrollNone means the binding is a button binding.
+	ScrollNone ScrollDirection = iota
+	ScrollUp
+	ScrollDown
+	ScrollLeft
+	ScrollRight
+)
+
+// scrollNames maps scroll names used in binding specs to directions.
+var scrollNames = map[string]ScrollDirection{
+	"scrollup":    ScrollUp,
+	"scrolldown":  ScrollDown,
+	"scrollleft":  ScrollLeft,
+	"scrollright": ScrollRight,
+}
+
+// PointerCombo is a button pressed, or a scroll in a direction, while holding
+// exactly the modifiers Mods.
+type PointerCombo struct {
+	Mods   uint32
+	Button uint32
+	Scroll ScrollDirection
+}
+
+// ParsePointerCombo parses a pointer combination like "Logo+Left" or
+// "Logo+ScrollUp". Modifiers are the same as for ParseKeyCombo. Buttons are
+// Left, Right, Middle, Side, Extra, Forward, Back and Task, or their
+// linux/input.h names like BTN_LEFT. Scroll directions are ScrollUp,
+// ScrollDown, ScrollLeft and ScrollRight. Names are matched case
+// insensitively.
+func ParsePointerCombo(spec string) (PointerCombo, error) {
+	var combo PointerCombo
+
+	parts := strings.Split(spec, "+")
+	for _, part := range parts[:len(parts)-1] {
+		mod, ok := modifierNames[strings.ToLower(strings.TrimSpace(part))]
+		if !ok {
+			return PointerCombo{}, fmt.Errorf("unknown modifier '%s' in '%s'", part, spec)
+		}
+		combo.Mods |= mod
+	}
+
+	name := strings.ToLower(strings.TrimSpace(parts[len(parts)-1]))
+	name = strings.TrimPrefix(name, "btn_")
+	if button, ok := buttonNames[name]; ok {
+		combo.Button = button
+		return combo, nil
+	}
+
+	if scroll, ok := scrollNames[name]; ok {
+		combo.Scroll = scroll
+		return combo, nil
+	}
+
+	return PointerCombo{}, fmt.Errorf("unknown button '%s' in '%s'", parts[len(parts)-1], spec)
+}
+
+// String gets the spec of the combination, e.g. "Logo+Left".
+func (p PointerCombo) String() string {
+	var b strings.Builder
+	for _, mod := range modifierOrder {
+		if p.Mods&mod.bit != 0 {
+			b.WriteString(mod.name)
+			b.WriteByte('+')
+		}
+	}
+
+	switch p.Scroll {
+	case ScrollUp:
+		b.WriteString("ScrollUp")
+	case ScrollDown:
+		b.WriteString("ScrollDown")
+	case ScrollLeft:
+		b.WriteString("ScrollLeft")
+	case ScrollRight:
+		b.WriteString("ScrollRight")
+	default:
+		name := fmt.Sprintf("0x%x", p.Button)
+		for n, button := range buttonNames {
+			if button == p.Button {
+				name = strings.ToUpper(n[:1]) + n[1:]
+				break
+			}
+		}
+		b.WriteString(name)
+	}
+
+	return b.String()
+}
+
+// PointerBinding binds a pointer combination to an action.
+type PointerBinding struct {
+	Combo PointerCombo
+	// Release fires the action when the button is released instead of
+	// pressed. Not used by scroll bindings.
+	Release bool
+	// Action is called with the view under the pointer, or 0, and the
+	// pointer position.
+	Action func(view View, pos Point)
+	// Description describes the binding when listed.
+	Description string
+}
+
+// String gets the spec of the binding combination.
+func (b *PointerBinding) String() string {
+	return b.Combo.String()
+}
+
+// PointerBindings matches pointer button and scroll events against pointer
+// bindings. Modifiers are matched exactly, ignoring IgnoreMods.
+//
+// Scroll amounts are accumulated per direction, and a scroll binding fires
+// once for every ScrollStep scrolled, so smooth scrolling devices don't fire
+// on every event.
+//
+// Call PointerButton and PointerScroll from the respective callbacks before
+// any other handling of the event.
+type PointerBindings struct {
+	// IgnoreMods are modifiers ignored when matching, by default Caps and
+	// Mod2 (Num Lock).
+	IgnoreMods uint32
+	// ScrollStep is the scroll amount firing a scroll binding once.
+	ScrollStep float64
+
+	bindings []*PointerBinding
+	// buttons whose press was consumed, so their release is consumed too.
+	consumed map[uint32]*PointerBinding
+	// accumulated scroll amount, vertical and horizontal.
+	scrolled [2]float64
+}
+
+// NewPointerBindings initializes pointer bindings with a scroll step of 10,
+// one notch of a typical mouse wheel.
+func NewPointerBindings() *PointerBindings {
+	return &PointerBindings{
+		IgnoreMods: BitModCaps | BitModMod2,
+		ScrollStep: 10,
+		consumed:   make(map[uint32]*PointerBinding),
+	}
+}
+
+// Add adds binding. Returns error if the binding conflicts with an existing
+// binding.
+func (p *PointerBindings) Add(binding PointerBinding) (*PointerBinding, error) {
+	if binding.Combo.Scroll != ScrollNone {
+		binding.Release = false
+	}
+
+	for _, other := range p.bindings {
+		if other.Combo == binding.Combo && other.Release == binding.Release {
+			return nil, fmt.Errorf("'%s' conflicts with '%s'", &binding, other)
+		}
+	}
+
+	b := &binding
+	p.bindings = append(p.bindings, b)
+	return b, nil
+}
+
+// Bind binds spec, parsed by ParsePointerCombo, to action.
+func (p *PointerBindings) Bind(spec string, action func(view View, pos Point)) (*PointerBinding, error) {
+	combo, err := ParsePointerCombo(spec)
+	if err != nil {
+		return nil, err
+	}
+	return p.Add(PointerBinding{Combo: combo, Action: action})
+}
+
+// BindRelease is like Bind but fires action when the button is released.
+func (p *PointerBindings) BindRelease(spec string, action func(view View, pos Point)) (*PointerBinding, error) {
+	combo, err := ParsePointerCombo(spec)
+	if err != nil {
+		return nil, err
+	}
+	return p.Add(PointerBinding{Combo: combo, Release: true, Action: action})
+}
+
+// Remove removes binding.
+func (p *PointerBindings) Remove(binding *PointerBinding) {
+	for i, b := range p.bindings {
+		if b == binding {
+			p.bindings = append(p.bindings[:i], p.bindings[i+1:]...)
+			break
+		}
+	}
+
+	for button, b := range p.consumed {
+		if b == binding {
+			p.consumed[button] = nil
+		}
+	}
+}
+
+// Bindings gets all bindings in the order they were added.
+func (p *PointerBindings) Bindings() []*PointerBinding {
+	bindings := make([]*PointerBinding, len(p.bindings))
+	copy(bindings, p.bindings)
+	return bindings
+}
+
+// PointerButton runs the action of the binding matching the button event.
+// Returns true if the event is part of a binding, meaning it's not sent to
+// clients.
+func (p *PointerBindings) PointerButton(view View, time uint32, modifiers Modifiers, button uint32, state ButtonState, pos *Point) bool {
+	if state != ButtonStatePressed {
+		b, ok := p.consumed[button]
+		if !ok {
+			return false
+		}
+
+		delete(p.consumed, button)
+		if b != nil && b.Action != nil {
+			b.Action(view, *pos)
+		}
+		return true
+	}
+
+	combo := PointerCombo{Mods: modifiers.Mods &^ p.IgnoreMods, Button: button}
+
+	var press, release *PointerBinding
+	for _, b := range p.bindings {
+		if b.Combo != combo {
+			continue
+		}
+
+		if b.Release {
+			release = b
+		} else {
+			press = b
+		}
+	}
+
+	if press == nil && release == nil {
+		return false
+	}
+
+	p.consumed[button] = release
+	if press != nil && press.Action != nil {
+		press.Action(view, *pos)
+	}
+	return true
+}
+
+// PointerScroll runs the action of the binding matching the scroll event.
+// Returns true if the event is part of a binding, meaning it's not sent to
+// clients.
+func (p *PointerBindings) PointerScroll(view View, time uint32, modifiers Modifiers, axis uint8, amount [2]float64) bool {
+	return p.scroll(view, modifiers.Mods, axis, amount, PointerGetPosition)
+}
+
+// scroll handles a scroll event, getting the pointer position from position
+// when an action runs.
+func (p *PointerBindings) scroll(view View, mods uint32, axis uint8, amount [2]float64, position func() *Point) bool {
+	mods &^= p.IgnoreMods
+	handled := false
+
+	axes := [2]struct {
+		bit      uint8
+		negative ScrollDirection
+		positive ScrollDirection
+	}{
+		{uint8(ScrollAxisVertical), ScrollUp, ScrollDown},
+		{ScrollAxisHorizontal, ScrollLeft, ScrollRight},
+	}
+
+	for i, a := range axes {
+		if axis&a.bit == 0 || amount[i] == 0 {
+			continue
+		}
+
+		dir := a.positive
+		if amount[i] < 0 {
+			dir = a.negative
+		}
+
+		b := p.scrollBinding(PointerCombo{Mods: mods, Scroll: dir})
+		if b == nil {
+			p.scrolled[i] = 0
+			continue
+		}
+		handled = true
+
+		// start over when changing direction.
+		if (p.scrolled[i] < 0) != (amount[i] < 0) {
+			p.scrolled[i] = 0
+		}
+		p.scrolled[i] += amount[i]
+
+		for p.ScrollStep <= 0 || p.scrolled[i] >= p.ScrollStep || p.scrolled[i] <= -p.ScrollStep {
+			if b.Action != nil {
+				b.Action(view, *position())
+			}
+
+			if p.ScrollStep <= 0 {
+				p.scrolled[i] = 0
+				break
+			}
+
+			if p.scrolled[i] > 0 {
+				p.scrolled[i] -= p.ScrollStep
+			} else {
+				p.scrolled[i] += p.ScrollStep
+			}
+		}
+	}
+
+	return handled
+}
+
+func (p *PointerBindings) scrollBinding(combo PointerCombo) *PointerBinding {
+	for _, b := range p.bindings {
+		if b.Combo == combo {
+			return b
+		}
+	}
+	return nil
+}
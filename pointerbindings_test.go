@@ -0,0 +1,121 @@
+package wlc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePointerCombo(t *testing.T) {
+	for _, tc := range []struct {
+		spec string
+		exp  string
+	}{
+		{"Logo+Left", "Logo+Left"},
+		{"ctrl+shift+middle", "Ctrl+Shift+Middle"},
+		{"Alt+BTN_RIGHT", "Alt+Right"},
+		{"Logo+ScrollUp", "Logo+ScrollUp"},
+		{"scrollright", "ScrollRight"},
+	} {
+		combo, err := ParsePointerCombo(tc.spec)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.spec, err)
+			continue
+		}
+
+		if combo.String() != tc.exp {
+			t.Errorf("%s: expected '%s', got '%s'", tc.spec, tc.exp, combo)
+		}
+	}
+
+	for _, spec := range []string{"", "Logo+", "Hyper+Left", "Logo+Wheel"} {
+		if _, err := ParsePointerCombo(spec); err == nil {
+			t.Errorf("'%s': expected error", spec)
+		}
+	}
+}
+
+func TestPointerBindings(t *testing.T) {
+	p := NewPointerBindings()
+
+	var fired []string
+	bind := func(spec string, release bool) {
+		b := p.Bind
+		if release {
+			b = p.BindRelease
+		}
+
+		if _, err := b(spec, func(View, Point) { fired = append(fired, spec) }); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bind("Logo+Left", false)
+	bind("Logo+Right", true)
+	bind("Logo+ScrollDown", false)
+
+	if _, err := p.Bind("logo+btn_left", nil); err == nil {
+		t.Error("expected conflict with Logo+Left")
+	}
+
+	logo := Modifiers{Mods: BitModLogo}
+	pos := &Point{}
+	for _, tc := range []struct {
+		name     string
+		mods     Modifiers
+		button   uint32
+		state    ButtonState
+		consumed bool
+		fired    []string
+	}{
+		{"press binding", logo, ButtonLeft, ButtonStatePressed, true, []string{"Logo+Left"}},
+		{"press binding release", Modifiers{}, ButtonLeft, ButtonStateReleased, true, nil},
+		{"release binding", logo, ButtonRight, ButtonStatePressed, true, nil},
+		{"release binding release", Modifiers{}, ButtonRight, ButtonStateReleased, true, []string{"Logo+Right"}},
+		{"missing modifier", Modifiers{}, ButtonLeft, ButtonStatePressed, false, nil},
+		{"missing modifier release", Modifiers{}, ButtonLeft, ButtonStateReleased, false, nil},
+		{"ignored modifier", Modifiers{Mods: BitModLogo | BitModMod2}, ButtonLeft, ButtonStatePressed, true, []string{"Logo+Left"}},
+	} {
+		fired = nil
+		if consumed := p.PointerButton(0, 0, tc.mods, tc.button, tc.state, pos); consumed != tc.consumed {
+			t.Errorf("%s: expected consumed %t, got %t", tc.name, tc.consumed, consumed)
+		}
+
+		if !reflect.DeepEqual(fired, tc.fired) {
+			t.Errorf("%s: expected %v to fire, got %v", tc.name, tc.fired, fired)
+		}
+	}
+}
+
+func TestPointerBindingsScroll(t *testing.T) {
+	p := NewPointerBindings()
+
+	fired := 0
+	if _, err := p.Bind("Logo+ScrollDown", func(View, Point) { fired++ }); err != nil {
+		t.Fatal(err)
+	}
+
+	position := func() *Point { return &Point{} }
+	vertical := uint8(ScrollAxisVertical)
+	for _, tc := range []struct {
+		name     string
+		mods     uint32
+		amount   float64
+		consumed bool
+		fired    int
+	}{
+		{"less than a step", BitModLogo, 6, true, 0},
+		{"accumulated step", BitModLogo, 6, true, 1},
+		{"two steps", BitModLogo, 20, true, 2},
+		{"other direction", BitModLogo, -20, false, 0},
+		{"missing modifier", 0, 20, false, 0},
+	} {
+		fired = 0
+		if consumed := p.scroll(0, tc.mods, vertical, [2]float64{tc.amount, 0}, position); consumed != tc.consumed {
+			t.Errorf("%s: expected consumed %t, got %t", tc.name, tc.consumed, consumed)
+		}
+
+		if fired != tc.fired {
+			t.Errorf("%s: expected %d actions, got %d", tc.name, tc.fired, fired)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package wlc
+
+// TypePolicy describes how views of a certain type are treated.
+type TypePolicy struct {
+	// Tiled means views are part of layouts.
+	Tiled bool
+	// Cycle means views are part of focus cycling.
+	Cycle bool
+	// Place sets the initial geometry of views. If nil the geometry
+	// requested by the view is kept.
+	Place func(View)
+}
+
+// DefaultTypePolicy is the policy of views without any special type bits.
+var DefaultTypePolicy = TypePolicy{Tiled: true, Cycle: true}
+
+// typePrecedence is the order in which type bits decide the policy of a view
+// with multiple type bits set.
+var typePrecedence = []ViewTypeBit{
+	BitOverrideRedirect,
+	BitUnmanaged,
+	BitPopup,
+	BitModal,
+	BitSplash,
+}
+
+// ViewPolicies decides how views are treated based on their type bits.
+// Unmanaged and override-redirect views are kept out of layouts and focus
+// cycling, splash and modal views are centered over their parent or output
+// and popups are placed relative to their parent. Each behavior can be
+// overridden per type by changing Policies.
+//
+// Call ViewCreated from the view created callback to apply placement, and
+// use Tiled and Cycle to filter views in layouts and focus cycling, e.g. by
+// setting FocusManager.Skip.
+type ViewPolicies struct {
+	Policies map[ViewTypeBit]TypePolicy
+}
+
+// NewViewPolicies initializes view policies with the default policy for each
+// type.
+func NewViewPolicies() *ViewPolicies {
+	return &ViewPolicies{
+		Policies: map[ViewTypeBit]TypePolicy{
+			BitOverrideRedirect: {},
+			BitUnmanaged:        {},
+			BitPopup:            {Place: PlaceRelativeToParent},
+			BitModal:            {Cycle: true, Place: CenterOverParent},
+			BitSplash:           {Place: CenterOverParent},
+		},
+	}
+}
+
+// Policy gets the policy for view. If view has multiple type bits the
+// policy is chosen in the order override-redirect, unmanaged, popup, modal,
+// splash.
+func (p *ViewPolicies) Policy(view View) TypePolicy {
+	typ := view.GetType()
+	for _, bit := range typePrecedence {
+		if typ&uint32(bit) == 0 {
+			continue
+		}
+
+		if policy, ok := p.Policies[bit]; ok {
+			return policy
+		}
+	}
+
+	return DefaultTypePolicy
+}
+
+// Tiled checks if view should be part of layouts.
+func (p *ViewPolicies) Tiled(view View) bool {
+	return p.Policy(view).Tiled
+}
+
+// Cycle checks if view should be part of focus cycling.
+func (p *ViewPolicies) Cycle(view View) bool {
+	return p.Policy(view).Cycle
+}
+
+// FilterTiled gets the views that should be part of layouts.
+func (p *ViewPolicies) FilterTiled(views []View) []View {
+	tiled := make([]View, 0, len(views))
+	for _, view := range views {
+		if p.Tiled(view) {
+			tiled = append(tiled, view)
+		}
+	}
+	return tiled
+}
+
+// ViewCreated places view according to its policy. Always returns true so
+// it can be used directly as view created callback.
+func (p *ViewPolicies) ViewCreated(view View) bool {
+	if place := p.Policy(view).Place; place != nil {
+		place(view)
+	}
+	return true
+}
+
+// CenterOverParent centers view over its parent, or over its output if it has
+// no parent.
+func CenterOverParent(view View) {
+	g := view.GetGeometry()
+	if g == nil {
+		return
+	}
+
+	if parent := view.GetParent(); parent != 0 {
+		if pg := parent.GetGeometry(); pg != nil {
+			c := centerGeometry(pg.Size, g.Size)
+			c.Origin.X += pg.Origin.X
+			c.Origin.Y += pg.Origin.Y
+			view.SetGeometry(0, c)
+			return
+		}
+	}
+
+	if res := view.GetOutput().GetVirtualResolution(); res != nil {
+		view.SetGeometry(0, centerGeometry(*res, g.Size))
+	}
+}
+
+// PlaceRelativeToParent places view relative to its parent. Views with an
+// xdg-shell v6 positioner are placed with View.PlacePopup inside their
+// output, other Wayland popups have their requested origin offset by the
+// origin of the parent. X11 views, which have no surface role, already
+// request a position relative to the output and are left alone.
+func PlaceRelativeToParent(view View) {
+	if view.GetPositioner() != nil {
+		var bounds Geometry
+		if res := view.GetOutput().GetVirtualResolution(); res != nil {
+			bounds.Size = *res
+		}
+		view.SetGeometry(0, view.PlacePopup(bounds))
+		return
+	}
+
+	parent := view.GetParent()
+	if parent == 0 || view.GetRole() == nil {
+		return
+	}
+
+	g, pg := view.GetGeometry(), parent.GetGeometry()
+	if g == nil || pg == nil {
+		return
+	}
+
+	g.Origin.X += pg.Origin.X
+	g.Origin.Y += pg.Origin.Y
+	view.SetGeometry(0, *g)
+}
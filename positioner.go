@@ -0,0 +1,211 @@
+package wlc
+
+// Positioner describes how a popup should be placed relative to its parent,
+// as defined in xdg-shell v6.
+type Positioner struct {
+	Size                 Size
+	AnchorRect           Geometry
+	Offset               Point
+	Anchor               PositionerAnchorBit
+	Gravity              PositionerGravityBit
+	ConstraintAdjustment PositionerConstraintAdjustmentBit
+}
+
+// GetPositioner gets the positioner of view. Returns nil if view has no valid
+// positioner.
+func (v View) GetPositioner() *Positioner {
+	size := v.PositionerGetSize()
+	anchorRect := v.PositionerGetAnchorRect()
+	if size == nil || anchorRect == nil {
+		return nil
+	}
+
+	p := &Positioner{
+		Size:                 *size,
+		AnchorRect:           *anchorRect,
+		Anchor:               v.PositionerGetAnchor(),
+		Gravity:              v.PositionerGetGravity(),
+		ConstraintAdjustment: v.PositionerGetConstraintAdjustment(),
+	}
+
+	if offset := v.PositionerGetOffset(); offset != nil {
+		p.Offset = *offset
+	}
+
+	return p
+}
+
+// PlacePopup computes the geometry of a popup view from its positioner. The
+// anchor rectangle is relative to the geometry of the parent view, the
+// returned geometry is in the same coordinate space as bounds, usually the
+// output. If the view has no valid positioner the current geometry of view is
+// returned.
+func (v View) PlacePopup(bounds Geometry) Geometry {
+	p := v.GetPositioner()
+	if p == nil {
+		if g := v.GetGeometry(); g != nil {
+			return *g
+		}
+		return GeometryZero
+	}
+
+	var origin Point
+	if parent := v.GetParent(); parent != 0 {
+		if g := parent.GetGeometry(); g != nil {
+			origin = g.Origin
+		}
+	}
+
+	return p.Place(origin, bounds)
+}
+
+// Place computes the popup geometry with the anchor rectangle relative to
+// parent. The popup is adjusted to fit inside bounds according to the
+// constraint adjustment. Adjustments are tried in the order flip, slide,
+// resize for each axis, as defined by xdg-shell v6. A bounds of size 0x0
+// disables the constraint adjustments.
+func (p *Positioner) Place(parent Point, bounds Geometry) Geometry {
+	anchor := uint32(p.Anchor)
+	gravity := uint32(p.Gravity)
+	adjust := uint32(p.ConstraintAdjustment)
+
+	x, w := positionerAxis{
+		anchorPos:    int64(parent.X) + int64(p.AnchorRect.Origin.X),
+		anchorLen:    int64(p.AnchorRect.Size.W),
+		size:         int64(p.Size.W),
+		offset:       int64(p.Offset.X),
+		anchorStart:  anchor&BitAnchorLeft != 0,
+		anchorEnd:    anchor&BitAnchorRight != 0,
+		gravityStart: gravity&BitGravityLeft != 0,
+		gravityEnd:   gravity&BitGravityRight != 0,
+		flip:         adjust&BitConstraintAdjustmentFlipX != 0,
+		slide:        adjust&BitConstraintAdjustmentSlideX != 0,
+		resize:       adjust&BitConstraintAdjustmentResizeX != 0,
+	}.constrain(int64(bounds.Origin.X), int64(bounds.Size.W))
+
+	y, h := positionerAxis{
+		anchorPos:    int64(parent.Y) + int64(p.AnchorRect.Origin.Y),
+		anchorLen:    int64(p.AnchorRect.Size.H),
+		size:         int64(p.Size.H),
+		offset:       int64(p.Offset.Y),
+		anchorStart:  anchor&BitAnchorTop != 0,
+		anchorEnd:    anchor&BitAnchorBottom != 0,
+		gravityStart: gravity&BitGravityTop != 0,
+		gravityEnd:   gravity&BitGravityBottom != 0,
+		flip:         adjust&BitConstraintAdjustmentFlipY != 0,
+		slide:        adjust&BitConstraintAdjustmentSlideY != 0,
+		resize:       adjust&BitConstraintAdjustmentResizeY != 0,
+	}.constrain(int64(bounds.Origin.Y), int64(bounds.Size.H))
+
+	return Geometry{
+		Origin: Point{X: int32(x), Y: int32(y)},
+		Size:   Size{W: uint32(w), H: uint32(h)},
+	}
+}
+
+// positionerAxis is a positioner projected on a single axis. Start is the
+// left or top edge and end is the right or bottom edge.
+type positionerAxis struct {
+	anchorPos, anchorLen     int64
+	size, offset             int64
+	anchorStart, anchorEnd   bool
+	gravityStart, gravityEnd bool
+	flip, slide, resize      bool
+}
+
+// place gets the unconstrained position of the popup. Setting both or none
+// of the start and end bits centers on the anchor rectangle.
+func (a positionerAxis) place() int64 {
+	pos := a.anchorPos
+	switch {
+	case a.anchorStart && !a.anchorEnd:
+	case a.anchorEnd && !a.anchorStart:
+		pos += a.anchorLen
+	default:
+		pos += a.anchorLen / 2
+	}
+
+	switch {
+	case a.gravityStart && !a.gravityEnd:
+		pos -= a.size
+	case a.gravityEnd && !a.gravityStart:
+	default:
+		pos -= a.size / 2
+	}
+
+	return pos + a.offset
+}
+
+// flipped swaps the anchor and gravity edges and inverts the offset.
+func (a positionerAxis) flipped() positionerAxis {
+	a.anchorStart, a.anchorEnd = a.anchorEnd, a.anchorStart
+	a.gravityStart, a.gravityEnd = a.gravityEnd, a.gravityStart
+	a.offset = -a.offset
+	return a
+}
+
+// constrain places the popup inside bounds. Returns position and length of
+// the popup. A popup allowed to be adjusted that is still entirely outside
+// bounds after the adjustments is moved into bounds, without any adjustment
+// the position is left alone.
+func (a positionerAxis) constrain(boundsPos, boundsLen int64) (int64, int64) {
+	pos, length := a.place(), a.size
+	if boundsLen == 0 {
+		return pos, length
+	}
+
+	end := boundsPos + boundsLen
+	fits := func(pos, length int64) bool {
+		return pos >= boundsPos && pos+length <= end
+	}
+
+	if fits(pos, length) {
+		return pos, length
+	}
+
+	if a.flip {
+		// the flipped position is only used if it's no longer constrained.
+		if flipped := a.flipped().place(); fits(flipped, length) {
+			return flipped, length
+		}
+	}
+
+	if a.slide {
+		if pos+length > end {
+			pos = end - length
+		}
+		if pos < boundsPos {
+			pos = boundsPos
+		}
+		if fits(pos, length) {
+			return pos, length
+		}
+	}
+
+	if a.resize {
+		last := pos + length
+		if pos < boundsPos {
+			pos = boundsPos
+		}
+		if last > end {
+			last = end
+		}
+		if last > pos {
+			length = last - pos
+		}
+	}
+
+	// as a last resort move the popup into bounds, instead of placing it
+	// where it can't be seen at all.
+	adjust := a.flip || a.slide || a.resize
+	if adjust && (pos >= end || pos+length <= boundsPos) {
+		if pos+length > end {
+			pos = end - length
+		}
+		if pos < boundsPos {
+			pos = boundsPos
+		}
+	}
+
+	return pos, length
+}
@@ -0,0 +1,117 @@
+package wlc
+
+import "testing"
+
+func TestPositionerPlace(t *testing.T) {
+	bounds := Geometry{Size: Size{W: 100, H: 100}}
+
+	for _, tc := range []struct {
+		name   string
+		p      Positioner
+		parent Point
+		exp    Geometry
+	}{
+		{
+			name: "anchor and gravity bottom right",
+			p: Positioner{
+				Size:       Size{W: 10, H: 10},
+				AnchorRect: Geometry{Origin: Point{X: 10, Y: 10}, Size: Size{W: 20, H: 20}},
+				Anchor:     BitAnchorBottom | BitAnchorRight,
+				Gravity:    BitGravityBottom | BitGravityRight,
+			},
+			exp: Geometry{Origin: Point{X: 30, Y: 30}, Size: Size{W: 10, H: 10}},
+		},
+		{
+			name: "centered relative to parent",
+			p: Positioner{
+				Size:       Size{W: 10, H: 10},
+				AnchorRect: Geometry{Origin: Point{X: 10, Y: 10}, Size: Size{W: 20, H: 20}},
+			},
+			parent: Point{X: 5, Y: 5},
+			exp:    Geometry{Origin: Point{X: 20, Y: 20}, Size: Size{W: 10, H: 10}},
+		},
+		{
+			name: "flip",
+			p: Positioner{
+				Size:                 Size{W: 20, H: 10},
+				AnchorRect:           Geometry{Origin: Point{X: 90}, Size: Size{W: 10, H: 10}},
+				Anchor:               BitAnchorRight,
+				Gravity:              BitGravityRight,
+				ConstraintAdjustment: BitConstraintAdjustmentFlipX,
+			},
+			exp: Geometry{Origin: Point{X: 70}, Size: Size{W: 20, H: 10}},
+		},
+		{
+			name: "slide",
+			p: Positioner{
+				Size:                 Size{W: 20, H: 10},
+				AnchorRect:           Geometry{Origin: Point{X: 90}, Size: Size{W: 10, H: 10}},
+				Anchor:               BitAnchorRight,
+				Gravity:              BitGravityRight,
+				ConstraintAdjustment: BitConstraintAdjustmentSlideX,
+			},
+			exp: Geometry{Origin: Point{X: 80}, Size: Size{W: 20, H: 10}},
+		},
+		{
+			name: "resize",
+			p: Positioner{
+				Size:                 Size{W: 20, H: 10},
+				AnchorRect:           Geometry{Origin: Point{X: 85}, Size: Size{W: 10, H: 10}},
+				Anchor:               BitAnchorRight,
+				Gravity:              BitGravityRight,
+				ConstraintAdjustment: BitConstraintAdjustmentResizeX,
+			},
+			exp: Geometry{Origin: Point{X: 95}, Size: Size{W: 5, H: 10}},
+		},
+		{
+			name: "slide and resize",
+			p: Positioner{
+				Size:                 Size{W: 20, H: 200},
+				AnchorRect:           Geometry{Size: Size{W: 10, H: 10}},
+				Anchor:               BitAnchorBottom,
+				Gravity:              BitGravityBottom,
+				ConstraintAdjustment: BitConstraintAdjustmentSlideY | BitConstraintAdjustmentResizeY,
+			},
+			exp: Geometry{Origin: Point{X: -5}, Size: Size{W: 20, H: 100}},
+		},
+		{
+			// resize can't shrink the popup into bounds, the resize bit
+			// still allows moving it there.
+			name: "resize fails",
+			p: Positioner{
+				Size:                 Size{W: 20, H: 10},
+				AnchorRect:           Geometry{Origin: Point{X: 90}, Size: Size{W: 10, H: 10}},
+				Anchor:               BitAnchorRight,
+				Gravity:              BitGravityRight,
+				ConstraintAdjustment: BitConstraintAdjustmentResizeX,
+			},
+			exp: Geometry{Origin: Point{X: 80}, Size: Size{W: 20, H: 10}},
+		},
+		{
+			name: "no adjustment outside bounds",
+			p: Positioner{
+				Size:       Size{W: 20, H: 10},
+				AnchorRect: Geometry{Origin: Point{X: 100, Y: 100}, Size: Size{W: 10, H: 10}},
+				Anchor:     BitAnchorBottom | BitAnchorRight,
+				Gravity:    BitGravityBottom | BitGravityRight,
+			},
+			// without adjustment bits the popup is placed as requested.
+			exp: Geometry{Origin: Point{X: 110, Y: 110}, Size: Size{W: 20, H: 10}},
+		},
+		{
+			name: "adjustment on other axis outside bounds",
+			p: Positioner{
+				Size:                 Size{W: 20, H: 10},
+				AnchorRect:           Geometry{Origin: Point{X: 100, Y: 100}, Size: Size{W: 10, H: 10}},
+				Anchor:               BitAnchorBottom | BitAnchorRight,
+				Gravity:              BitGravityBottom | BitGravityRight,
+				ConstraintAdjustment: BitConstraintAdjustmentSlideX,
+			},
+			exp: Geometry{Origin: Point{X: 80, Y: 110}, Size: Size{W: 20, H: 10}},
+		},
+	} {
+		if g := tc.p.Place(tc.parent, bounds); !GeometryEquals(g, tc.exp) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.exp, g)
+		}
+	}
+}
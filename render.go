@@ -4,6 +4,19 @@ package wlc
 #cgo LDFLAGS: -lwlc
 #include <stdlib.h>
 #include <wlc/wlc-render.h>
+
+// geometries are passed by value to keep them off the Go heap.
+static inline void pixels_write(enum wlc_pixel_format format, struct wlc_geometry geometry, const void *data) {
+	wlc_pixels_write(format, &geometry, data);
+}
+
+static inline void pixels_read(enum wlc_pixel_format format, struct wlc_geometry geometry, struct wlc_geometry *out_geometry, void *out_data) {
+	wlc_pixels_read(format, &geometry, out_geometry, out_data);
+}
+
+static inline void surface_render(wlc_resource surface, struct wlc_geometry geometry) {
+	wlc_surface_render(surface, &geometry);
+}
 */
 import "C"
 
@@ -24,9 +37,7 @@ const (
 // framebuffer. If geometry is out of bounds, it will be automatically clamped.
 // TODO: make more go friendly
 func PixelsWrite(format PixelFormat, geometry Geometry, data unsafe.Pointer) {
-	cgeometry := geometry.c()
-	defer C.free(unsafe.Pointer(cgeometry))
-	C.wlc_pixels_write(C.enum_wlc_pixel_format(format), cgeometry, data)
+	C.pixels_write(C.enum_wlc_pixel_format(format), geometry.c(), data)
 }
 
 // PixelsRead read pixel data from output's framebuffer.
@@ -35,18 +46,14 @@ func PixelsWrite(format PixelFormat, geometry Geometry, data unsafe.Pointer) {
 // width / height of the returned data.
 // TODO: make more go friendly
 func PixelsRead(format PixelFormat, geometry Geometry, outGeometry *Geometry, outData unsafe.Pointer) {
-	cgeometry := geometry.c()
-	defer C.free(unsafe.Pointer(cgeometry))
 	var cgOut C.struct_wlc_geometry
-	C.wlc_pixels_read(C.enum_wlc_pixel_format(format), cgeometry, &cgOut, outData)
+	C.pixels_read(C.enum_wlc_pixel_format(format), geometry.c(), &cgOut, outData)
 	geometryCtoGo(outGeometry, &cgOut)
 }
 
 // Render renders surfaces inside post / pre render hooks.
 func (s Resource) Render(geometry Geometry) {
-	cgeometry := geometry.c()
-	defer C.free(unsafe.Pointer(cgeometry))
-	C.wlc_surface_render(C.wlc_resource(s), cgeometry)
+	C.surface_render(C.wlc_resource(s), geometry.c())
 }
 
 // ScheduleRender schedules output for rendering next frame.
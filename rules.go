@@ -0,0 +1,293 @@
+package wlc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Matcher matches a string property of a view such as title or app id.
+// *regexp.Regexp satisfies this interface.
+type Matcher interface {
+	MatchString(s string) bool
+}
+
+type exactMatcher string
+
+func (m exactMatcher) MatchString(s string) bool {
+	return string(m) == s
+}
+
+// MatchExact returns a matcher matching only s.
+func MatchExact(s string) Matcher {
+	return exactMatcher(s)
+}
+
+// MatchGlob returns a matcher matching the shell style pattern. '*' matches
+// any sequence of characters and '?' matches any single character. Unlike
+// path.Match, '/' is not treated specially.
+func MatchGlob(pattern string) Matcher {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
+
+// compileGlob is like MatchGlob, but reports patterns that are not valid
+// UTF-8.
+func compileGlob(pattern string) (Matcher, error) {
+	if !utf8.ValidString(pattern) {
+		return nil, fmt.Errorf("invalid glob pattern %q: not valid UTF-8", pattern)
+	}
+
+	return MatchGlob(pattern), nil
+}
+
+// MatchRegexp returns a matcher matching the regular expression expr.
+func MatchRegexp(expr string) (Matcher, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return re, nil
+}
+
+// ViewRuleActions describes what to do with a view matched by a ViewRule.
+type ViewRuleActions struct {
+	// Float marks the view as floating. wlc has no notion of floating
+	// views, so this is only recorded for the compositor to query.
+	Float bool
+	// Output moves the view to output, if not 0.
+	Output Output
+	// Mask sets the visibility bitmask of the view, if not nil.
+	Mask *uint32
+	// Geometry sets the geometry of the view, if not nil.
+	Geometry *Geometry
+	// Fullscreen makes the view fullscreen on its output, see
+	// ViewRules.SetFullscreen.
+	Fullscreen bool
+	// Focus focuses the view.
+	Focus bool
+	// NoFocus marks the view as one that should not get focus. Like Float
+	// this is only recorded for the compositor to query.
+	NoFocus bool
+}
+
+// merge applies the set actions of o on top of a.
+func (a *ViewRuleActions) merge(o ViewRuleActions) {
+	a.Float = a.Float || o.Float
+	a.Fullscreen = a.Fullscreen || o.Fullscreen
+	if o.Output != 0 {
+		a.Output = o.Output
+	}
+	if o.Mask != nil {
+		a.Mask = o.Mask
+	}
+	if o.Geometry != nil {
+		a.Geometry = o.Geometry
+	}
+	if o.Focus {
+		a.Focus, a.NoFocus = true, false
+	}
+	if o.NoFocus {
+		a.Focus, a.NoFocus = false, true
+	}
+}
+
+// ViewRule matches views on their properties. Nil matchers and zero values
+// match any view. All set fields must match for the rule to match.
+type ViewRule struct {
+	Name     string
+	Title    Matcher
+	Class    Matcher
+	Instance Matcher
+	AppID    Matcher
+	PID      int
+	// Type is a ViewTypeBit bitfield of bits that must be set.
+	Type uint32
+	// Parent requires the view to have a parent matching this rule.
+	Parent  *ViewRule
+	Actions ViewRuleActions
+}
+
+// Match checks if view matches rule.
+func (r *ViewRule) Match(view View) bool {
+	if r.Title != nil && !r.Title.MatchString(view.Title()) {
+		return false
+	}
+
+	if r.Class != nil && !r.Class.MatchString(view.GetClass()) {
+		return false
+	}
+
+	if r.Instance != nil && !r.Instance.MatchString(view.Instance()) {
+		return false
+	}
+
+	if r.AppID != nil && !r.AppID.MatchString(view.GetAppID()) {
+		return false
+	}
+
+	if r.PID != 0 && r.PID != view.GetPID() {
+		return false
+	}
+
+	if view.GetType()&r.Type != r.Type {
+		return false
+	}
+
+	if r.Parent != nil {
+		parent := view.GetParent()
+		if parent == 0 || !r.Parent.Match(parent) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ViewRules is an ordered list of view rules. Rules are evaluated when a view
+// is created and re-evaluated when its properties are updated, actions of a
+// rule are only applied the first time the rule matches a view.
+//
+// Call ViewCreated, ViewDestroyed and ViewPropertiesUpdated from the
+// callbacks set with SetViewCreatedCb, SetViewDestroyedCb and
+// SetViewPropertiesUpdatedCb.
+type ViewRules struct {
+	// SetFullscreen makes a view fullscreen for the Fullscreen action, e.g.
+	// StateManager.SetFullscreen so the geometry of the view is restored
+	// when it leaves fullscreen. If nil the fullscreen state and geometry
+	// are set directly and the previous geometry is lost.
+	SetFullscreen func(view View, toggle bool)
+
+	rules []*ViewRule
+	hits  map[View][]*ViewRule
+}
+
+// NewViewRules initializes rules.
+func NewViewRules(rules ...*ViewRule) *ViewRules {
+	return &ViewRules{
+		rules: rules,
+		hits:  make(map[View][]*ViewRule),
+	}
+}
+
+// Add appends rule to the list of rules. The rule is only evaluated for
+// views created or updated after it was added.
+func (r *ViewRules) Add(rule *ViewRule) {
+	r.rules = append(r.rules, rule)
+}
+
+// ViewCreated evaluates the rules for view. Always returns true so it can be
+// used directly as view created callback.
+func (r *ViewRules) ViewCreated(view View) bool {
+	r.Evaluate(view)
+	return true
+}
+
+// ViewDestroyed forgets rule hits of view.
+func (r *ViewRules) ViewDestroyed(view View) {
+	delete(r.hits, view)
+}
+
+// ViewPropertiesUpdated re-evaluates the rules for view.
+func (r *ViewRules) ViewPropertiesUpdated(view View, mask ViewPropertyUpdateBit) {
+	r.Evaluate(view)
+}
+
+// Evaluate matches view against all rules and applies the actions of rules
+// matching for the first time. Rules that no longer match are dropped from
+// the hits of view.
+func (r *ViewRules) Evaluate(view View) {
+	old := r.hits[view]
+	hits := make([]*ViewRule, 0, len(r.rules))
+	var actions ViewRuleActions
+	for _, rule := range r.rules {
+		if !rule.Match(view) {
+			continue
+		}
+
+		hits = append(hits, rule)
+		if !containsRule(old, rule) {
+			actions.merge(rule.Actions)
+		}
+	}
+
+	r.hits[view] = hits
+	r.apply(view, actions)
+}
+
+// Hits gets the rules currently matching view, in rule order.
+func (r *ViewRules) Hits(view View) []*ViewRule {
+	hits := make([]*ViewRule, len(r.hits[view]))
+	copy(hits, r.hits[view])
+	return hits
+}
+
+// Actions gets the combined actions of all rules currently matching view.
+// Later rules take precedence.
+func (r *ViewRules) Actions(view View) ViewRuleActions {
+	var actions ViewRuleActions
+	for _, rule := range r.hits[view] {
+		actions.merge(rule.Actions)
+	}
+	return actions
+}
+
+// Floating checks if view is matched by a rule with the Float action.
+func (r *ViewRules) Floating(view View) bool {
+	return r.Actions(view).Float
+}
+
+// NoFocus checks if view is matched by a rule with the NoFocus action.
+func (r *ViewRules) NoFocus(view View) bool {
+	return r.Actions(view).NoFocus
+}
+
+func (r *ViewRules) apply(view View, actions ViewRuleActions) {
+	if actions.Output != 0 {
+		view.SetOutput(actions.Output)
+	}
+
+	if actions.Mask != nil {
+		setViewMask(view, *actions.Mask)
+	}
+
+	if actions.Geometry != nil {
+		view.SetGeometry(0, *actions.Geometry)
+	}
+
+	if actions.Fullscreen && r.SetFullscreen != nil {
+		r.SetFullscreen(view, true)
+	} else if actions.Fullscreen {
+		if res := view.GetOutput().GetVirtualResolution(); res != nil {
+			view.SetState(BitFullscreen, true)
+			view.SetGeometry(0, Geometry{Size: *res})
+		}
+	}
+
+	if actions.Focus {
+		view.Focus()
+	}
+}
+
+func containsRule(rules []*ViewRule, rule *ViewRule) bool {
+	for _, r := range rules {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
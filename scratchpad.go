@@ -0,0 +1,184 @@
+package wlc
+
+// Scratchpad keeps views out of sight and summons them on demand. Views in
+// the scratchpad are hidden by clearing their visibility bitmask and shown
+// centered on the focused output, above everything else.
+//
+// Call ViewDestroyed and OutputDestroyed from the respective callbacks.
+type Scratchpad struct {
+	// RaiseView brings a view to front, e.g. Layers.Raise. If nil
+	// View.BringToFront is used.
+	RaiseView func(View)
+
+	views []View
+	shown map[View]bool
+	// current is the index of the view shown by Toggle and Cycle.
+	current int
+}
+
+// NewScratchpad initializes an empty scratchpad.
+func NewScratchpad() *Scratchpad {
+	return &Scratchpad{
+		shown: make(map[View]bool),
+	}
+}
+
+// Views gets the views in the scratchpad in the order they were added.
+func (s *Scratchpad) Views() []View {
+	views := make([]View, len(s.views))
+	copy(views, s.views)
+	return views
+}
+
+// Contains checks if view is in the scratchpad.
+func (s *Scratchpad) Contains(view View) bool {
+	return s.index(view) != -1
+}
+
+// Shown checks if view is in the scratchpad and currently shown.
+func (s *Scratchpad) Shown(view View) bool {
+	return s.shown[view]
+}
+
+// Add moves view to the scratchpad and hides it.
+func (s *Scratchpad) Add(view View) {
+	if !s.Contains(view) {
+		s.views = append(s.views, view)
+	}
+	s.Hide(view)
+}
+
+// Remove removes view from the scratchpad. The view is shown on the focused
+// output if it was hidden.
+func (s *Scratchpad) Remove(view View) {
+	i := s.index(view)
+	if i == -1 {
+		return
+	}
+
+	if !s.shown[view] {
+		s.Show(view)
+	}
+
+	s.forget(i)
+}
+
+// Hide hides view if it is in the scratchpad.
+func (s *Scratchpad) Hide(view View) {
+	if !s.Contains(view) {
+		return
+	}
+
+	delete(s.shown, view)
+	setViewMask(view, 0)
+}
+
+// Show shows view centered on the focused output, above all other views, and
+// focuses it. Does nothing if view is not in the scratchpad or there are no
+// outputs.
+func (s *Scratchpad) Show(view View) {
+	if !s.Contains(view) {
+		return
+	}
+
+	output := GetFocusedOutput()
+	if output == 0 {
+		outputs := GetOutputs()
+		if len(outputs) == 0 {
+			return
+		}
+		output = outputs[0]
+	}
+
+	view.SetOutput(output)
+	setViewMask(view, output.GetMask())
+	if res, g := output.GetVirtualResolution(), view.GetGeometry(); res != nil && g != nil {
+		view.SetGeometry(0, centerGeometry(*res, g.Size))
+	}
+	raiseView(s.RaiseView, view)
+	view.Focus()
+	s.shown[view] = true
+}
+
+// Toggle hides the current scratchpad view if it is shown on the focused
+// output, otherwise it is shown.
+func (s *Scratchpad) Toggle() {
+	if len(s.views) == 0 {
+		return
+	}
+
+	view := s.views[s.current]
+	if s.shown[view] && view.GetOutput() == GetFocusedOutput() {
+		s.Hide(view)
+		return
+	}
+
+	s.Show(view)
+}
+
+// Cycle hides the current scratchpad view and shows the next one.
+func (s *Scratchpad) Cycle() {
+	if len(s.views) == 0 {
+		return
+	}
+
+	s.Hide(s.views[s.current])
+	s.current = (s.current + 1) % len(s.views)
+	s.Show(s.views[s.current])
+}
+
+// ViewDestroyed removes view from the scratchpad.
+func (s *Scratchpad) ViewDestroyed(view View) {
+	if i := s.index(view); i != -1 {
+		s.forget(i)
+	}
+}
+
+// OutputDestroyed hides scratchpad views shown on output, they are shown on
+// the focused output the next time they are summoned.
+func (s *Scratchpad) OutputDestroyed(output Output) {
+	for view := range s.shown {
+		if view.GetOutput() == output {
+			s.Hide(view)
+		}
+	}
+}
+
+func (s *Scratchpad) forget(i int) {
+	delete(s.shown, s.views[i])
+	s.views = append(s.views[:i], s.views[i+1:]...)
+	if s.current > i || s.current >= len(s.views) {
+		s.current--
+	}
+	if s.current < 0 {
+		s.current = 0
+	}
+}
+
+func (s *Scratchpad) index(view View) int {
+	for i, v := range s.views {
+		if v == view {
+			return i
+		}
+	}
+	return -1
+}
+
+// centerGeometry centers size inside an area of the given resolution. The
+// origin is never negative, so the top left corner of the view stays
+// visible.
+func centerGeometry(resolution, size Size) Geometry {
+	x := (int64(resolution.W) - int64(size.W)) / 2
+	y := (int64(resolution.H) - int64(size.H)) / 2
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+
+	return Geometry{
+		Origin: Point{X: int32(x), Y: int32(y)},
+		Size:   size,
+	}
+}
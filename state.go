@@ -0,0 +1,179 @@
+package wlc
+
+// StateManager honors maximize and fullscreen requests of views. The geometry
+// of a view is saved when it becomes maximized or fullscreen and restored
+// when both states are removed again. Fullscreen takes precedence over
+// maximized, so a view that is both is shown fullscreen.
+//
+// Call ViewRequestState, ViewCreated, ViewDestroyed, ViewMoveToOutput and
+// OutputResolution from the respective callbacks.
+type StateManager struct {
+	// MaximizeArea gets the area of output used for maximized views. If nil
+	// the full output is used.
+	MaximizeArea func(Output) Geometry
+	// RaiseView brings a view to front, e.g. Layers.Raise. If nil
+	// View.BringToFront is used.
+	RaiseView func(View)
+
+	views map[View]*viewState
+}
+
+type viewState struct {
+	state ViewStateBit
+	saved Geometry
+}
+
+// NewStateManager initializes a state manager.
+func NewStateManager() *StateManager {
+	return &StateManager{
+		views: make(map[View]*viewState),
+	}
+}
+
+// ViewRequestState applies the requested state. Maximized and fullscreen
+// states are managed, other states are applied as is. Can be used directly
+// as view request state callback.
+func (s *StateManager) ViewRequestState(view View, state ViewStateBit, toggle bool) {
+	switch state {
+	case BitMaximized, BitFullscreen:
+		s.set(view, state, toggle)
+	default:
+		view.SetState(state, toggle)
+	}
+}
+
+// SetMaximized maximizes or restores view.
+func (s *StateManager) SetMaximized(view View, toggle bool) {
+	s.set(view, BitMaximized, toggle)
+}
+
+// SetFullscreen makes view fullscreen or restores it.
+func (s *StateManager) SetFullscreen(view View, toggle bool) {
+	s.set(view, BitFullscreen, toggle)
+}
+
+// Maximized checks if view is maximized by the manager.
+func (s *StateManager) Maximized(view View) bool {
+	vs, ok := s.views[view]
+	return ok && vs.state&BitMaximized != 0
+}
+
+// Fullscreen checks if view is fullscreen by the manager.
+func (s *StateManager) Fullscreen(view View) bool {
+	vs, ok := s.views[view]
+	return ok && vs.state&BitFullscreen != 0
+}
+
+// SavedGeometry gets the geometry view had before it was maximized or made
+// fullscreen. Returns nil if view is in neither state.
+func (s *StateManager) SavedGeometry(view View) *Geometry {
+	vs, ok := s.views[view]
+	if !ok {
+		return nil
+	}
+	g := vs.saved
+	return &g
+}
+
+// ViewCreated keeps fullscreen views on the output of view on top, unless
+// view is a child of the fullscreen view. Always returns true so it can be
+// used directly as view created callback.
+func (s *StateManager) ViewCreated(view View) bool {
+	s.raise(view.GetOutput(), view)
+	return true
+}
+
+// ViewDestroyed forgets view.
+func (s *StateManager) ViewDestroyed(view View) {
+	delete(s.views, view)
+}
+
+// ViewMoveToOutput re-applies the state of view on its new output.
+func (s *StateManager) ViewMoveToOutput(view View, from, to Output) {
+	if _, ok := s.views[view]; ok {
+		s.apply(view)
+	}
+}
+
+// OutputResolution re-applies the state of all managed views on output.
+func (s *StateManager) OutputResolution(output Output, from, to *Size) {
+	for view := range s.views {
+		if view.GetOutput() == output {
+			s.apply(view)
+		}
+	}
+}
+
+// Raise brings the fullscreen views of output to front.
+func (s *StateManager) Raise(output Output) {
+	s.raise(output, 0)
+}
+
+func (s *StateManager) raise(output Output, except View) {
+	var parent View
+	if except != 0 {
+		parent = except.GetParent()
+	}
+
+	for _, view := range output.GetViews() {
+		if view != except && (parent == 0 || view != parent) && s.Fullscreen(view) {
+			raiseView(s.RaiseView, view)
+		}
+	}
+}
+
+func (s *StateManager) set(view View, state ViewStateBit, toggle bool) {
+	vs, ok := s.views[view]
+	if toggle && !ok {
+		g := view.GetGeometry()
+		if g == nil {
+			return
+		}
+		vs = &viewState{saved: *g}
+		s.views[view] = vs
+	}
+
+	if !ok && !toggle {
+		view.SetState(state, false)
+		return
+	}
+
+	if toggle {
+		vs.state |= state
+	} else {
+		vs.state &^= state
+	}
+	view.SetState(state, toggle)
+
+	if vs.state == 0 {
+		delete(s.views, view)
+		view.SetGeometry(0, vs.saved)
+		return
+	}
+
+	s.apply(view)
+}
+
+func (s *StateManager) apply(view View) {
+	vs := s.views[view]
+	output := view.GetOutput()
+
+	if vs.state&BitFullscreen != 0 {
+		res := output.GetVirtualResolution()
+		if res == nil {
+			return
+		}
+		view.SetGeometry(0, Geometry{Size: *res})
+		raiseView(s.RaiseView, view)
+		return
+	}
+
+	if s.MaximizeArea != nil {
+		view.SetGeometry(0, s.MaximizeArea(output))
+		return
+	}
+
+	if res := output.GetVirtualResolution(); res != nil {
+		view.SetGeometry(0, Geometry{Size: *res})
+	}
+}
@@ -0,0 +1,170 @@
+package wlc
+
+// ViewTree is an index of the parent/child relationships between views. wlc
+// only knows the parent of a view, ViewTree keeps the reverse mapping so
+// children and transients (dialogs etc.) can be found without scanning every
+// output.
+//
+// The tree must be fed from the view callbacks, by calling ViewCreated,
+// ViewDestroyed and ViewPropertiesUpdated from the callbacks set with
+// SetViewCreatedCb, SetViewDestroyedCb and SetViewPropertiesUpdatedCb.
+type ViewTree struct {
+	// RaiseView brings a view to front, e.g. Layers.Raise. If nil
+	// View.BringToFront is used.
+	RaiseView func(View)
+
+	parents  map[View]View
+	children map[View][]View
+}
+
+// NewViewTree initializes an empty view tree.
+func NewViewTree() *ViewTree {
+	return &ViewTree{
+		parents:  make(map[View]View),
+		children: make(map[View][]View),
+	}
+}
+
+// ViewCreated adds view to the tree. Always returns true so it can be used
+// directly as view created callback.
+func (t *ViewTree) ViewCreated(view View) bool {
+	t.link(view, view.GetParent())
+	return true
+}
+
+// ViewDestroyed removes view from the tree. Children of view become root
+// views.
+func (t *ViewTree) ViewDestroyed(view View) {
+	t.unlink(view)
+	for _, child := range t.children[view] {
+		t.parents[child] = 0
+	}
+	delete(t.children, view)
+}
+
+// ViewPropertiesUpdated refreshes the parent of view.
+func (t *ViewTree) ViewPropertiesUpdated(view View, mask ViewPropertyUpdateBit) {
+	t.Update(view)
+}
+
+// Update refreshes the parent of view as reported by wlc. Use this if the
+// parent was changed outside of the tree.
+func (t *ViewTree) Update(view View) {
+	parent := view.GetParent()
+	if t.parents[view] != parent {
+		t.unlink(view)
+		t.link(view, parent)
+	}
+}
+
+// SetParent sets parent of view and updates the tree.
+func (t *ViewTree) SetParent(view, parent View) {
+	view.SetParent(parent)
+	t.Update(view)
+}
+
+// Parent gets parent of view as known by the tree. Returns 0 if view has no
+// parent.
+func (t *ViewTree) Parent(view View) View {
+	return t.parents[view]
+}
+
+// Children gets the direct children of view in creation order.
+func (t *ViewTree) Children(view View) []View {
+	children := make([]View, len(t.children[view]))
+	copy(children, t.children[view])
+	return children
+}
+
+// Root gets the top most ancestor of view. Returns view itself if it has no
+// parent.
+func (t *ViewTree) Root(view View) View {
+	seen := map[View]bool{view: true}
+	for {
+		parent, ok := t.parents[view]
+		if !ok || parent == 0 || seen[parent] {
+			return view
+		}
+		seen[parent] = true
+		view = parent
+	}
+}
+
+// Descendants gets all children of view, recursively, in depth first order.
+// A parent is always listed before its children.
+func (t *ViewTree) Descendants(view View) []View {
+	var views []View
+	seen := map[View]bool{view: true}
+	t.walk(view, seen, func(v View) {
+		views = append(views, v)
+	})
+	return views
+}
+
+// Family gets the root of view followed by all its descendants.
+func (t *ViewTree) Family(view View) []View {
+	root := t.Root(view)
+	return append([]View{root}, t.Descendants(root)...)
+}
+
+// RaiseFamily brings the family of view to front, keeping every child above
+// its parent.
+func (t *ViewTree) RaiseFamily(view View) {
+	for _, v := range t.Family(view) {
+		raiseView(t.RaiseView, v)
+	}
+}
+
+// SetFamilyOutput moves the family of view to output.
+func (t *ViewTree) SetFamilyOutput(view View, output Output) {
+	for _, v := range t.Family(view) {
+		v.SetOutput(output)
+	}
+}
+
+// CloseFamily closes the family of view. Children are closed before their
+// parents.
+func (t *ViewTree) CloseFamily(view View) {
+	family := t.Family(view)
+	for i := len(family) - 1; i >= 0; i-- {
+		family[i].Close()
+	}
+}
+
+func (t *ViewTree) walk(view View, seen map[View]bool, fn func(View)) {
+	for _, child := range t.children[view] {
+		if seen[child] {
+			continue
+		}
+		seen[child] = true
+		fn(child)
+		t.walk(child, seen, fn)
+	}
+}
+
+func (t *ViewTree) link(view, parent View) {
+	t.parents[view] = parent
+	if parent != 0 {
+		t.children[parent] = append(t.children[parent], view)
+	}
+}
+
+func (t *ViewTree) unlink(view View) {
+	parent, ok := t.parents[view]
+	if !ok {
+		return
+	}
+
+	delete(t.parents, view)
+	children := t.children[parent]
+	for i, child := range children {
+		if child == view {
+			t.children[parent] = append(children[:i], children[i+1:]...)
+			break
+		}
+	}
+
+	if len(t.children[parent]) == 0 {
+		delete(t.children, parent)
+	}
+}
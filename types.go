@@ -10,10 +10,6 @@ import "C"
 // internally by wlc.
 type EventSource *C.struct_wlc_event_source
 
-// type XKBState *C.struct_xkb_state
-// type XKBKeymap *C.struct_xkb_keymap
-// type InputDevice *C.struct_libinput_device
-
 type LogType C.enum_wlc_log_type
 
 const (
@@ -173,8 +169,8 @@ type Modifiers struct {
 	Mods uint32
 }
 
-func (m *Modifiers) c() *C.struct_wlc_modifiers {
-	return &C.struct_wlc_modifiers{
+func (m *Modifiers) c() C.struct_wlc_modifiers {
+	return C.struct_wlc_modifiers{
 		leds: C.uint32_t(m.Leds),
 		mods: C.uint32_t(m.Mods),
 	}
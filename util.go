@@ -21,15 +21,6 @@ void char_array_free(char **arr) {
 	}
 	free(arr);
 }
-
-wlc_handle *handle_array_init(size_t len) {
-	wlc_handle *arr = malloc(len * sizeof(wlc_handle));
-	return arr;
-}
-
-void handle_array_insert(wlc_handle *arr, wlc_handle item, int index) {
-	arr[index] = item;
-}
 */
 import "C"
 
@@ -57,33 +48,35 @@ func freeCStrArray(arr **C.char) {
 	C.char_array_free(arr)
 }
 
+// outputHandlesCArraytoGoSlice copies a C array of handles to a Go slice. The
+// C array is owned by wlc and may change, so it's not referenced.
 func outputHandlesCArraytoGoSlice(handles *C.wlc_handle, len int) []Output {
 	goHandles := make([]Output, len)
-	size := int(unsafe.Sizeof(*handles))
-	for i := 0; i < len; i++ {
-		ptr := unsafe.Pointer(uintptr(unsafe.Pointer(handles)) + uintptr(size*i))
-		goHandles[i] = *(*Output)(ptr)
+	if handles != nil {
+		copy(goHandles, unsafe.Slice((*Output)(unsafe.Pointer(handles)), len))
 	}
 
 	return goHandles
 }
 
+// viewHandlesCArraytoGoSlice copies a C array of handles to a Go slice. The
+// C array is owned by wlc and may change, so it's not referenced.
 func viewHandlesCArraytoGoSlice(handles *C.wlc_handle, len int) []View {
 	goHandles := make([]View, len)
-	size := int(unsafe.Sizeof(*handles))
-	for i := 0; i < len; i++ {
-		ptr := unsafe.Pointer(uintptr(unsafe.Pointer(handles)) + uintptr(size*i))
-		goHandles[i] = *(*View)(ptr)
+	if handles != nil {
+		copy(goHandles, unsafe.Slice((*View)(unsafe.Pointer(handles)), len))
 	}
 
 	return goHandles
 }
 
-func viewHandlesSliceToCArray(arr []View) (*C.wlc_handle, C.size_t) {
-	carr := C.handle_array_init(C.size_t(len(arr)))
-	for i, h := range arr {
-		C.handle_array_insert(carr, C.wlc_handle(h), C.int(i))
+// viewHandlesSliceToC gets a C array pointing to the backing array of arr. View
+// has the same memory layout as wlc_handle, so no copy is needed. The
+// pointer is only valid for the duration of the C call it's passed to.
+func viewHandlesSliceToC(arr []View) (*C.wlc_handle, C.size_t) {
+	if len(arr) == 0 {
+		return nil, 0
 	}
 
-	return carr, C.size_t(len(arr))
+	return (*C.wlc_handle)(unsafe.Pointer(&arr[0])), C.size_t(len(arr))
 }
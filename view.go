@@ -2,13 +2,15 @@ package wlc
 
 /*
 #cgo LDFLAGS: -lwlc
-#include <stdlib.h>
 #include <wlc/wlc.h>
+
+// geometry is passed by value to keep it off the Go heap.
+static inline void view_set_geometry(wlc_handle view, uint32_t edges, struct wlc_geometry geometry) {
+	wlc_view_set_geometry(view, edges, &geometry);
+}
 */
 import "C"
 
-import "unsafe"
-
 // View is a wlc_handle describing a view object in wlc.
 type View C.wlc_handle
 
@@ -133,9 +135,7 @@ func (v View) GetVisibleGeometry() Geometry {
 // SetGeometry sets geometry. Set edges if the geometry change is caused by
 // interactive resize.
 func (v View) SetGeometry(edges uint32, geometry Geometry) {
-	cgeometry := geometry.c()
-	defer C.free(unsafe.Pointer(cgeometry))
-	C.wlc_view_set_geometry(C.wlc_handle(v), C.uint32_t(edges), cgeometry)
+	C.view_set_geometry(C.wlc_handle(v), C.uint32_t(edges), geometry.c())
 }
 
 // GetType gets type bitfield for view.
@@ -196,3 +196,9 @@ func (v View) GetAppID() string {
 func (v View) GetPID() int {
 	return int(C.wlc_view_get_pid(C.wlc_handle(v)))
 }
+
+// Visible checks if view is visible on its output, that is if the visibility
+// bitmask of view and output has any bits in common.
+func (v View) Visible() bool {
+	return v.GetMask()&v.GetOutput().GetMask() != 0
+}
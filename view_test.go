@@ -0,0 +1,13 @@
+package wlc
+
+import "testing"
+
+func BenchmarkViewSetGeometry(b *testing.B) {
+	view := View(1)
+	geometry := Geometry{Origin: Point{X: 10, Y: 20}, Size: Size{W: 640, H: 480}}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		view.SetGeometry(0, geometry)
+	}
+}
@@ -1,9 +1,23 @@
 package wlc
 
 /*
-#cgo LDFLAGS: -lwlc
+#cgo LDFLAGS: -lwlc -lwayland-server
 #include <stdlib.h>
+#include <string.h>
+#include <wayland-server.h>
 #include <wlc/wlc-wayland.h>
+
+static enum wl_iterator_result send_keyboard_modifiers_cb(struct wl_resource *resource, void *data) {
+	const uint32_t *m = data;
+	if (strcmp(wl_resource_get_class(resource), "wl_keyboard") == 0)
+		wl_keyboard_send_modifiers(resource, m[0], m[1], m[2], m[3], m[4]);
+	return WL_ITERATOR_CONTINUE;
+}
+
+static void send_keyboard_modifiers(struct wl_client *client, uint32_t depressed, uint32_t latched, uint32_t locked, uint32_t group) {
+	uint32_t m[] = {wl_display_next_serial(wlc_get_wl_display()), depressed, latched, locked, group};
+	wl_client_for_each_resource(client, send_keyboard_modifiers_cb, m);
+}
 */
 import "C"
 
@@ -67,12 +81,10 @@ func (v View) GetSurface() Resource {
 // GetSubsurfaces returns a list of subsurfaces for a surface.
 func (s Resource) GetSubsurfaces() []Resource {
 	var len C.size_t
-	resouces := C.wlc_surface_get_subsurfaces(C.wlc_resource(s), &len)
+	resources := C.wlc_surface_get_subsurfaces(C.wlc_resource(s), &len)
 	subsurfaces := make([]Resource, int(len))
-	size := int(unsafe.Sizeof(*resouces))
-	for i := 0; i < int(len); i++ {
-		ptr := unsafe.Pointer(uintptr(unsafe.Pointer(resouces)) + uintptr(size*i))
-		subsurfaces[i] = *(*Resource)(ptr)
+	if resources != nil {
+		copy(subsurfaces, unsafe.Slice((*Resource)(unsafe.Pointer(resources)), int(len)))
 	}
 	return subsurfaces
 }
@@ -96,3 +108,20 @@ func (v View) GetWlClient() *C.struct_wl_client {
 func (v View) GetRole() *C.struct_wl_resource {
 	return C.wlc_view_get_role(C.wlc_handle(v))
 }
+
+// SendKeyboardModifiers sends the modifiers and layout of state to the
+// keyboards of the client of view. wlc only sends them with key events, so
+// this is needed to tell clients about changes made with XKBState.
+func (v View) SendKeyboardModifiers(state *XKBState) {
+	client := v.GetWlClient()
+	if client == nil || state == nil {
+		return
+	}
+
+	depressed, latched, locked, layout := state.serialize()
+	C.send_keyboard_modifiers(client,
+		C.uint32_t(depressed),
+		C.uint32_t(latched),
+		C.uint32_t(locked),
+		C.uint32_t(layout))
+}
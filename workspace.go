@@ -0,0 +1,377 @@
+package wlc
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// maskSticky is the visibility bitmask of views visible on every workspace.
+const maskSticky = ^uint32(0)
+
+// Workspace is a named group of views. Each workspace owns one bit of the 32
+// bit visibility bitmask used by outputs and views, limiting the number of
+// workspaces to 32.
+type Workspace struct {
+	Name string
+	// Output is the output the workspace is assigned to. 0 means the
+	// workspace is shown on whatever output switches to it.
+	Output Output
+	mask   uint32
+}
+
+// Mask gets the visibility bitmask of the workspace.
+func (w *Workspace) Mask() uint32 {
+	return w.mask
+}
+
+// WorkspaceEvent describes a change of workspaces.
+type WorkspaceEvent int
+
+const (
+	// WorkspaceCreated is sent when a workspace is created.
+	WorkspaceCreated WorkspaceEvent = iota
+	// WorkspaceDestroyed is sent when a workspace is destroyed.
+	WorkspaceDestroyed
+	// WorkspaceShown is sent when a workspace is shown on an output.
+	WorkspaceShown
+	// WorkspaceHidden is sent when a workspace is no longer shown on an
+	// output.
+	WorkspaceHidden
+)
+
+// WorkspaceManager manages workspaces on top of output and view visibility
+// masks. Workspaces are created when switched to or when views are moved to
+// them, and destroyed when they are neither shown nor have any views.
+//
+// In per output mode every output shows its own workspace. In global mode
+// all outputs show the same workspace.
+//
+// Call ViewCreated, ViewDestroyed, OutputCreated and OutputDestroyed from the
+// respective callbacks.
+type WorkspaceManager struct {
+	Global bool
+	// OnChange is called on workspace changes, if not nil. output is 0
+	// for WorkspaceCreated and WorkspaceDestroyed.
+	OnChange func(event WorkspaceEvent, workspace *Workspace, output Output)
+
+	workspaces []*Workspace
+	current    map[Output]*Workspace
+	previous   map[Output]string
+	views      map[View]*Workspace
+	sticky     map[View]bool
+}
+
+// NewWorkspaceManager initializes a workspace manager.
+func NewWorkspaceManager(global bool) *WorkspaceManager {
+	return &WorkspaceManager{
+		Global:   global,
+		current:  make(map[Output]*Workspace),
+		previous: make(map[Output]string),
+		views:    make(map[View]*Workspace),
+		sticky:   make(map[View]bool),
+	}
+}
+
+// Workspaces gets all workspaces in creation order.
+func (m *WorkspaceManager) Workspaces() []*Workspace {
+	workspaces := make([]*Workspace, len(m.workspaces))
+	copy(workspaces, m.workspaces)
+	return workspaces
+}
+
+// Workspace gets workspace by name. Returns nil if it doesn't exist.
+func (m *WorkspaceManager) Workspace(name string) *Workspace {
+	for _, ws := range m.workspaces {
+		if ws.Name == name {
+			return ws
+		}
+	}
+	return nil
+}
+
+// Get gets workspace by name, creating it if it doesn't exist. Returns error
+// if all 32 workspaces are in use.
+func (m *WorkspaceManager) Get(name string) (*Workspace, error) {
+	if ws := m.Workspace(name); ws != nil {
+		return ws, nil
+	}
+
+	var used uint32
+	for _, ws := range m.workspaces {
+		used |= ws.mask
+	}
+
+	for bit := uint(0); bit < 32; bit++ {
+		if used&(1<<bit) == 0 {
+			ws := &Workspace{Name: name, mask: 1 << bit}
+			m.workspaces = append(m.workspaces, ws)
+			m.notify(WorkspaceCreated, ws, 0)
+			return ws, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no free workspace for '%s'", name)
+}
+
+// Current gets the workspace shown on output. Returns nil if output shows no
+// workspace.
+func (m *WorkspaceManager) Current(output Output) *Workspace {
+	return m.current[output]
+}
+
+// Views gets the views on workspace name in no particular order, not
+// including sticky views.
+func (m *WorkspaceManager) Views(name string) []View {
+	var views []View
+	for view, ws := range m.views {
+		if ws.Name == name && !m.sticky[view] {
+			views = append(views, view)
+		}
+	}
+	return views
+}
+
+// Switch shows workspace name on output, creating it if needed. If the
+// workspace is assigned to another output it's shown there instead, and that
+// output is focused. The views of the workspace are moved to the output. In
+// global mode the workspace is shown on all outputs.
+func (m *WorkspaceManager) Switch(output Output, name string) error {
+	ws, err := m.Get(name)
+	if err != nil {
+		return err
+	}
+
+	if ws.Output != 0 && ws.Output != output {
+		output = ws.Output
+		output.Focus()
+	}
+
+	if m.Global {
+		for _, o := range GetOutputs() {
+			m.show(o, ws)
+		}
+	} else {
+		// a workspace is only shown on one output at a time.
+		for o, cur := range m.current {
+			if cur == ws && o != output {
+				m.show(o, m.unused(o))
+			}
+		}
+		m.show(output, ws)
+
+		// the views of the workspace may be on another output.
+		for view, vws := range m.views {
+			if vws == ws && !m.sticky[view] && view.GetOutput() != output {
+				view.SetOutput(output)
+			}
+		}
+	}
+
+	m.gc()
+	return nil
+}
+
+// BackAndForth switches output back to the workspace it showed before the
+// last switch.
+func (m *WorkspaceManager) BackAndForth(output Output) error {
+	name, ok := m.previous[output]
+	if !ok {
+		return fmt.Errorf("no previous workspace")
+	}
+	return m.Switch(output, name)
+}
+
+// MoveView moves view to workspace name, creating it if needed. If the
+// workspace is shown on an output the view is moved to that output.
+func (m *WorkspaceManager) MoveView(view View, name string) error {
+	ws, err := m.Get(name)
+	if err != nil {
+		return err
+	}
+
+	m.views[view] = ws
+	if !m.sticky[view] {
+		setViewMask(view, ws.mask)
+	}
+
+	for output, cur := range m.current {
+		if cur == ws && view.GetOutput() != output {
+			view.SetOutput(output)
+			break
+		}
+	}
+
+	m.gc()
+	return nil
+}
+
+// Assign assigns workspace name to output, creating it if needed. Assigning
+// 0 lets the workspace be shown on any output.
+func (m *WorkspaceManager) Assign(name string, output Output) error {
+	ws, err := m.Get(name)
+	if err != nil {
+		return err
+	}
+
+	ws.Output = output
+	return nil
+}
+
+// SetSticky makes view visible on all workspaces, or only on its own
+// workspace.
+func (m *WorkspaceManager) SetSticky(view View, sticky bool) {
+	if sticky {
+		m.sticky[view] = true
+		setViewMask(view, maskSticky)
+		return
+	}
+
+	delete(m.sticky, view)
+	if ws, ok := m.views[view]; ok {
+		setViewMask(view, ws.mask)
+	}
+}
+
+// Sticky checks if view is sticky.
+func (m *WorkspaceManager) Sticky(view View) bool {
+	return m.sticky[view]
+}
+
+// ViewCreated puts view on the workspace shown on its output. Always returns
+// true so it can be used directly as view created callback.
+func (m *WorkspaceManager) ViewCreated(view View) bool {
+	if ws := m.current[view.GetOutput()]; ws != nil {
+		m.views[view] = ws
+		setViewMask(view, ws.mask)
+	}
+	return true
+}
+
+// ViewDestroyed forgets view and destroys its workspace if it's no longer
+// used.
+func (m *WorkspaceManager) ViewDestroyed(view View) {
+	delete(m.views, view)
+	delete(m.sticky, view)
+	m.gc()
+}
+
+// OutputCreated shows a workspace on output, either the first one assigned to
+// it that isn't shown, or the first workspace not in use. In global mode the
+// workspace of the other outputs is shown. Always returns true so it can be
+// used directly as output created callback.
+func (m *WorkspaceManager) OutputCreated(output Output) bool {
+	if m.Global {
+		for _, ws := range m.current {
+			m.show(output, ws)
+			return true
+		}
+	}
+
+	for _, ws := range m.workspaces {
+		if ws.Output == output && !m.shown(ws) {
+			m.show(output, ws)
+			return true
+		}
+	}
+
+	if ws := m.unused(output); ws != nil {
+		m.show(output, ws)
+	}
+	return true
+}
+
+// OutputDestroyed forgets the workspace shown on output.
+func (m *WorkspaceManager) OutputDestroyed(output Output) {
+	if ws := m.current[output]; ws != nil {
+		delete(m.current, output)
+		m.notify(WorkspaceHidden, ws, output)
+	}
+	delete(m.previous, output)
+	m.gc()
+}
+
+// unused gets the first workspace that is neither shown nor assigned to
+// another output, creating a new one named by the lowest free number if
+// needed. Returns nil if all workspaces are in use.
+func (m *WorkspaceManager) unused(output Output) *Workspace {
+	for _, ws := range m.workspaces {
+		if !m.shown(ws) && (ws.Output == 0 || ws.Output == output) {
+			return ws
+		}
+	}
+
+	for i := 1; ; i++ {
+		name := strconv.Itoa(i)
+		if m.Workspace(name) == nil {
+			ws, err := m.Get(name)
+			if err != nil {
+				return nil
+			}
+			return ws
+		}
+	}
+}
+
+func (m *WorkspaceManager) show(output Output, ws *Workspace) {
+	cur := m.current[output]
+	if cur == ws {
+		return
+	}
+
+	if cur != nil {
+		m.previous[output] = cur.Name
+		delete(m.current, output)
+		m.notify(WorkspaceHidden, cur, output)
+	}
+
+	if ws == nil {
+		output.SetMask(0)
+		return
+	}
+
+	m.current[output] = ws
+	output.SetMask(ws.mask)
+	m.notify(WorkspaceShown, ws, output)
+}
+
+func (m *WorkspaceManager) shown(ws *Workspace) bool {
+	for _, cur := range m.current {
+		if cur == ws {
+			return true
+		}
+	}
+	return false
+}
+
+// gc destroys workspaces that are neither shown, assigned to an output nor
+// have any views.
+func (m *WorkspaceManager) gc() {
+	used := make(map[*Workspace]bool)
+	for _, ws := range m.views {
+		used[ws] = true
+	}
+	for _, ws := range m.current {
+		used[ws] = true
+	}
+
+	workspaces := m.workspaces[:0]
+	var destroyed []*Workspace
+	for _, ws := range m.workspaces {
+		if used[ws] || ws.Output != 0 {
+			workspaces = append(workspaces, ws)
+		} else {
+			destroyed = append(destroyed, ws)
+		}
+	}
+	m.workspaces = workspaces
+
+	for _, ws := range destroyed {
+		m.notify(WorkspaceDestroyed, ws, 0)
+	}
+}
+
+func (m *WorkspaceManager) notify(event WorkspaceEvent, ws *Workspace, output Output) {
+	if m.OnChange != nil {
+		m.OnChange(event, ws, output)
+	}
+}
@@ -0,0 +1,226 @@
+package wlc
+
+/*
+#cgo LDFLAGS: -lxkbcommon
+#include <stdlib.h>
+#include <xkbcommon/xkbcommon.h>
+*/
+import "C"
+
+import "unsafe"
+
+// xkbKeycodeOffset is the offset between the evdev keycodes used by wlc and
+// xkb keycodes.
+const xkbKeycodeOffset = 8
+
+// XKBKeymap is a reference to struct xkb_keymap which is handled internally
+// by wlc. Keys are evdev keycodes, as used by the keyboard callbacks.
+type XKBKeymap struct {
+	keymap *C.struct_xkb_keymap
+}
+
+// NumLayouts gets the number of layouts in the keymap.
+func (k *XKBKeymap) NumLayouts() uint32 {
+	return uint32(C.xkb_keymap_num_layouts(k.keymap))
+}
+
+// LayoutName gets the name of layout idx, e.g. "English (US)". Returns ""
+// if the layout has no name.
+func (k *XKBKeymap) LayoutName(idx uint32) string {
+	return goStringOrEmpty(C.xkb_keymap_layout_get_name(k.keymap, C.xkb_layout_index_t(idx)))
+}
+
+// LayoutNames gets the names of all layouts in the keymap.
+func (k *XKBKeymap) LayoutNames() []string {
+	names := make([]string, k.NumLayouts())
+	for i := range names {
+		names[i] = k.LayoutName(uint32(i))
+	}
+	return names
+}
+
+// LayoutIndex gets the index of the layout named name. Returns false if there
+// is no such layout.
+func (k *XKBKeymap) LayoutIndex(name string) (uint32, bool) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	idx := C.xkb_keymap_layout_get_index(k.keymap, cname)
+	return uint32(idx), idx != C.XKB_LAYOUT_INVALID
+}
+
+// NumMods gets the number of modifiers in the keymap.
+func (k *XKBKeymap) NumMods() uint32 {
+	return uint32(C.xkb_keymap_num_mods(k.keymap))
+}
+
+// ModName gets the name of modifier idx, e.g. "Shift" or "Mod4".
+func (k *XKBKeymap) ModName(idx uint32) string {
+	return goStringOrEmpty(C.xkb_keymap_mod_get_name(k.keymap, C.xkb_mod_index_t(idx)))
+}
+
+// ModNames gets the names of all modifiers in the keymap.
+func (k *XKBKeymap) ModNames() []string {
+	names := make([]string, k.NumMods())
+	for i := range names {
+		names[i] = k.ModName(uint32(i))
+	}
+	return names
+}
+
+// ModIndex gets the index of the modifier named name. Returns false if there
+// is no such modifier.
+func (k *XKBKeymap) ModIndex(name string) (uint32, bool) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	idx := C.xkb_keymap_mod_get_index(k.keymap, cname)
+	return uint32(idx), idx != C.XKB_MOD_INVALID
+}
+
+// NumLeds gets the number of leds in the keymap.
+func (k *XKBKeymap) NumLeds() uint32 {
+	return uint32(C.xkb_keymap_num_leds(k.keymap))
+}
+
+// LedName gets the name of led idx, e.g. "Caps Lock".
+func (k *XKBKeymap) LedName(idx uint32) string {
+	return goStringOrEmpty(C.xkb_keymap_led_get_name(k.keymap, C.xkb_led_index_t(idx)))
+}
+
+// LedNames gets the names of all leds in the keymap.
+func (k *XKBKeymap) LedNames() []string {
+	names := make([]string, k.NumLeds())
+	for i := range names {
+		names[i] = k.LedName(uint32(i))
+	}
+	return names
+}
+
+// KeyName gets the xkb name of key, e.g. "AE01". Returns "" if the key isn't
+// in the keymap.
+func (k *XKBKeymap) KeyName(key uint32) string {
+	return goStringOrEmpty(C.xkb_keymap_key_get_name(k.keymap, C.xkb_keycode_t(key+xkbKeycodeOffset)))
+}
+
+// KeyByName gets the key with the xkb name name. Returns false if there is no
+// such key.
+func (k *XKBKeymap) KeyByName(name string) (uint32, bool) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	key := C.xkb_keymap_key_by_name(k.keymap, cname)
+	if key == C.XKB_KEYCODE_INVALID || key < xkbKeycodeOffset {
+		return 0, false
+	}
+	return uint32(key) - xkbKeycodeOffset, true
+}
+
+// ForEachKey calls fn for every key in the keymap, in keycode order.
+func (k *XKBKeymap) ForEachKey(fn func(key uint32)) {
+	min := uint32(C.xkb_keymap_min_keycode(k.keymap))
+	max := uint32(C.xkb_keymap_max_keycode(k.keymap))
+	if min < xkbKeycodeOffset {
+		min = xkbKeycodeOffset
+	}
+
+	for key := min; key <= max; key++ {
+		if C.xkb_keymap_key_get_name(k.keymap, C.xkb_keycode_t(key)) != nil {
+			fn(key - xkbKeycodeOffset)
+		}
+	}
+}
+
+// KeySyms gets the keysyms produced by key in layout at shift level level.
+func (k *XKBKeymap) KeySyms(key, layout, level uint32) []uint32 {
+	var syms *C.xkb_keysym_t
+	n := C.xkb_keymap_key_get_syms_by_level(k.keymap, C.xkb_keycode_t(key+xkbKeycodeOffset), C.xkb_layout_index_t(layout), C.uint32_t(level), &syms)
+	if n <= 0 || syms == nil {
+		return nil
+	}
+
+	keysyms := make([]uint32, int(n))
+	copy(keysyms, unsafe.Slice((*uint32)(unsafe.Pointer(syms)), int(n)))
+	return keysyms
+}
+
+// KeyRepeats checks if key should repeat when held.
+func (k *XKBKeymap) KeyRepeats(key uint32) bool {
+	return C.xkb_keymap_key_repeats(k.keymap, C.xkb_keycode_t(key+xkbKeycodeOffset)) != 0
+}
+
+// XKBState is a reference to struct xkb_state which is handled internally by
+// wlc. Keys are evdev keycodes, as used by the keyboard callbacks.
+type XKBState struct {
+	state *C.struct_xkb_state
+}
+
+// Keymap gets the keymap of the state.
+func (s *XKBState) Keymap() *XKBKeymap {
+	return &XKBKeymap{keymap: C.xkb_state_get_keymap(s.state)}
+}
+
+// Layout gets the index of the active layout.
+func (s *XKBState) Layout() uint32 {
+	return uint32(C.xkb_state_serialize_layout(s.state, C.XKB_STATE_LAYOUT_EFFECTIVE))
+}
+
+// LayoutName gets the name of the active layout.
+func (s *XKBState) LayoutName() string {
+	return s.Keymap().LayoutName(s.Layout())
+}
+
+// SetLayout locks the active layout to idx, keeping the modifiers. Depressed
+// and latched layouts are reset, so idx is the effective layout. wlc sends the
+// new layout to clients with the next key event, use
+// View.SendKeyboardModifiers to send it right away.
+func (s *XKBState) SetLayout(idx uint32) {
+	C.xkb_state_update_mask(s.state,
+		C.xkb_state_serialize_mods(s.state, C.XKB_STATE_MODS_DEPRESSED),
+		C.xkb_state_serialize_mods(s.state, C.XKB_STATE_MODS_LATCHED),
+		C.xkb_state_serialize_mods(s.state, C.XKB_STATE_MODS_LOCKED),
+		0, 0, C.xkb_layout_index_t(idx))
+}
+
+// serialize gets the depressed, latched and locked modifiers and the
+// effective layout, as sent to clients.
+func (s *XKBState) serialize() (depressed, latched, locked, layout uint32) {
+	return uint32(C.xkb_state_serialize_mods(s.state, C.XKB_STATE_MODS_DEPRESSED)),
+		uint32(C.xkb_state_serialize_mods(s.state, C.XKB_STATE_MODS_LATCHED)),
+		uint32(C.xkb_state_serialize_mods(s.state, C.XKB_STATE_MODS_LOCKED)),
+		uint32(C.xkb_state_serialize_layout(s.state, C.XKB_STATE_LAYOUT_EFFECTIVE))
+}
+
+// ModActive checks if the modifier named name, e.g. "Shift", is active.
+func (s *XKBState) ModActive(name string) bool {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	return C.xkb_state_mod_name_is_active(s.state, cname, C.XKB_STATE_MODS_EFFECTIVE) > 0
+}
+
+// LedActive checks if the led named name, e.g. "Caps Lock", is on.
+func (s *XKBState) LedActive(name string) bool {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	return C.xkb_state_led_name_is_active(s.state, cname) > 0
+}
+
+// KeySym gets the keysym produced by key in the current state. Returns 0 if
+// key produces no or more than one keysym.
+func (s *XKBState) KeySym(key uint32) uint32 {
+	return uint32(C.xkb_state_key_get_one_sym(s.state, C.xkb_keycode_t(key+xkbKeycodeOffset)))
+}
+
+// KeyLayout gets the index of the layout used by key in the current state.
+func (s *XKBState) KeyLayout(key uint32) uint32 {
+	return uint32(C.xkb_state_key_get_layout(s.state, C.xkb_keycode_t(key+xkbKeycodeOffset)))
+}
+
+func goStringOrEmpty(str *C.char) string {
+	if str == nil {
+		return ""
+	}
+	return C.GoString(str)
+}